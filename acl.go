@@ -0,0 +1,160 @@
+package osc
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+)
+
+type accessRule struct {
+	allow   bool
+	network *net.IPNet
+	// pattern is nil for rules that cover every address
+	pattern *AddressPattern
+}
+
+// AccessList is a Handler that only passes on messages from sources allowed to send to their address
+//
+// A deny rule matching the source always wins, then an allow rule matching the source. A source no rule matches is
+// denied if there is an allow rule covering the address and allowed otherwise, so allowing one subnet to /master/*
+// keeps everyone else off /master/* without closing other addresses. Sources without an IP, like unix sockets, never
+// match a rule.
+type AccessList struct {
+	Handler Handler
+	// OnDeny is called with each message denied, nil ignores them
+	OnDeny func(message *OSCMessage, source net.Addr)
+
+	rules  []accessRule
+	denied int
+	mutex  sync.Mutex
+}
+
+func NewAccessList(handler Handler) *AccessList {
+	return &AccessList{
+		Handler: handler,
+		rules:   []accessRule{},
+	}
+}
+
+// Allow lets sources in cidr, an IP or CIDR block, send messages with an address matching pattern, an empty pattern covers every address
+func (a *AccessList) Allow(cidr string, pattern string) error {
+	return a.addRule(true, cidr, pattern)
+}
+
+// Deny stops sources in cidr, an IP or CIDR block, sending messages with an address matching pattern, an empty pattern covers every address
+func (a *AccessList) Deny(cidr string, pattern string) error {
+	return a.addRule(false, cidr, pattern)
+}
+
+func (a *AccessList) addRule(allow bool, cidr string, pattern string) error {
+	network, err := parseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+	rule := accessRule{
+		allow:   allow,
+		network: network,
+	}
+	if pattern != "" {
+		rule.pattern, err = CompileAddressPattern(pattern)
+		if err != nil {
+			return err
+		}
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.rules = append(a.rules, rule)
+	return nil
+}
+
+// Allowed reports whether source may send a message to address
+func (a *AccessList) Allowed(source net.Addr, address string) bool {
+	ip := sourceAddrIP(source)
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	allowed := false
+	addressHasAllowRule := false
+	for _, rule := range a.rules {
+		if rule.pattern != nil && !rule.pattern.Match(address) {
+			continue
+		}
+		if rule.allow {
+			addressHasAllowRule = true
+		}
+		if ip == nil || !rule.network.Contains(ip) {
+			continue
+		}
+		if !rule.allow {
+			return false
+		}
+		allowed = true
+	}
+	return allowed || !addressHasAllowRule
+}
+
+// Denied is the number of messages dropped so far
+func (a *AccessList) Denied() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.denied
+}
+
+func (a *AccessList) ServeOSC(w PacketWriter, packet OSCPacket, source net.Addr) {
+	if allowed := a.prune(packet, source); allowed != nil {
+		a.Handler.ServeOSC(w, allowed, source)
+	}
+}
+
+// prune returns the packet with the denied messages removed or nil if nothing is left
+func (a *AccessList) prune(packet OSCPacket, source net.Addr) OSCPacket {
+	return pruneMessages(packet, func(message *OSCMessage) bool {
+		if a.Allowed(source, message.Address) {
+			return true
+		}
+		a.mutex.Lock()
+		a.denied++
+		a.mutex.Unlock()
+		if a.OnDeny != nil {
+			a.OnDeny(message, source)
+		}
+		return false
+	})
+}
+
+// parseCIDR accepts a CIDR block or a single IP
+func parseCIDR(cidr string) (*net.IPNet, error) {
+	if strings.Contains(cidr, "/") {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP or CIDR: %s", cidr)
+		}
+		return network, nil
+	}
+
+	ip := net.ParseIP(cidr)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP or CIDR: %s", cidr)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+func sourceAddrIP(source net.Addr) net.IP {
+	switch source := source.(type) {
+	case *net.UDPAddr:
+		if source != nil {
+			return source.IP
+		}
+	case *net.TCPAddr:
+		if source != nil {
+			return source.IP
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package osc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestAccessList(t *testing.T) {
+	foh := &net.UDPAddr{IP: net.IPv4(10, 0, 1, 20), Port: 9000}
+	stage := &net.UDPAddr{IP: net.IPv4(10, 0, 2, 30), Port: 9000}
+	dualStackFOH := &net.TCPAddr{IP: net.ParseIP("::ffff:10.0.1.20"), Port: 9000}
+	ipv6 := &net.UDPAddr{IP: net.ParseIP("fd00::5"), Port: 9000}
+	unixSocket := &net.UnixAddr{Name: "/tmp/osc.sock", Net: "unixgram"}
+
+	type rule struct {
+		allow   bool
+		cidr    string
+		pattern string
+	}
+
+	testCases := []struct {
+		name     string
+		rules    []rule
+		source   net.Addr
+		address  string
+		expected bool
+	}{
+		{name: "no rules", source: stage, address: "/master/fader", expected: true},
+		{name: "allow list match", rules: []rule{{allow: true, cidr: "10.0.1.0/24"}}, source: foh, address: "/ch/1", expected: true},
+		{name: "allow list miss", rules: []rule{{allow: true, cidr: "10.0.1.0/24"}}, source: stage, address: "/ch/1", expected: false},
+		{name: "single IP", rules: []rule{{allow: true, cidr: "10.0.1.20"}}, source: foh, address: "/ch/1", expected: true},
+		{name: "deny list match", rules: []rule{{allow: false, cidr: "10.0.2.30"}}, source: stage, address: "/ch/1", expected: false},
+		{name: "deny list miss", rules: []rule{{allow: false, cidr: "10.0.2.30"}}, source: foh, address: "/ch/1", expected: true},
+		{name: "deny wins over allow", rules: []rule{{allow: true, cidr: "10.0.0.0/16"}, {allow: false, cidr: "10.0.2.0/24"}}, source: stage, address: "/ch/1", expected: false},
+		{name: "pattern allow for the subnet", rules: []rule{{allow: true, cidr: "10.0.1.0/24", pattern: "/master/*"}}, source: foh, address: "/master/fader", expected: true},
+		{name: "pattern allow keeps others off", rules: []rule{{allow: true, cidr: "10.0.1.0/24", pattern: "/master/*"}}, source: stage, address: "/master/fader", expected: false},
+		{name: "pattern allow leaves other addresses open", rules: []rule{{allow: true, cidr: "10.0.1.0/24", pattern: "/master/*"}}, source: stage, address: "/ch/1", expected: true},
+		{name: "pattern deny", rules: []rule{{allow: false, cidr: "10.0.2.0/24", pattern: "/master/*"}}, source: stage, address: "/master/fader", expected: false},
+		{name: "IPv4 mapped IPv6 source", rules: []rule{{allow: true, cidr: "10.0.1.0/24"}}, source: dualStackFOH, address: "/ch/1", expected: true},
+		{name: "IPv6 CIDR", rules: []rule{{allow: true, cidr: "fd00::/64"}}, source: ipv6, address: "/ch/1", expected: true},
+		{name: "source without an IP only gets the default", rules: []rule{{allow: true, cidr: "10.0.1.0/24"}}, source: unixSocket, address: "/ch/1", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			accessList := NewAccessList(nil)
+			for _, rule := range testCase.rules {
+				var err error
+				if rule.allow {
+					err = accessList.Allow(rule.cidr, rule.pattern)
+				} else {
+					err = accessList.Deny(rule.cidr, rule.pattern)
+				}
+				if err != nil {
+					t.Fatalf("failed to add rule: %s", err.Error())
+				}
+			}
+
+			got := accessList.Allowed(testCase.source, testCase.address)
+			if got != testCase.expected {
+				t.Fatalf("failed to check access got %t, expected %t", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestAccessListServeOSC(t *testing.T) {
+	count := 0
+	accessList := NewAccessList(countingHandler(&count))
+	err := accessList.Allow("10.0.1.0/24", "/master/*")
+	if err != nil {
+		t.Fatalf("failed to add rule: %s", err.Error())
+	}
+	var deniedAddress string
+	accessList.OnDeny = func(message *OSCMessage, source net.Addr) {
+		deniedAddress = message.Address
+	}
+
+	stage := &net.UDPAddr{IP: net.IPv4(10, 0, 2, 30), Port: 9000}
+	bundle := &OSCBundle{TimeTag: ImmediateTimeTag(), Contents: []OSCPacket{
+		&OSCMessage{Address: "/master/fader", Args: []OSCArg{}},
+		&OSCMessage{Address: "/ch/1", Args: []OSCArg{}},
+	}}
+	accessList.ServeOSC(nil, bundle, stage)
+
+	if count != 1 {
+		t.Fatalf("failed to prune bundle got %d messages through, expected 1", count)
+	}
+	if deniedAddress != "/master/fader" {
+		t.Fatalf("failed to report denied message got '%s', expected '%s'", deniedAddress, "/master/fader")
+	}
+	if accessList.Denied() != 1 {
+		t.Fatalf("failed to count denied messages got %d, expected 1", accessList.Denied())
+	}
+}
+
+func TestBadAccessListRules(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		cidr     string
+		pattern  string
+		errorMsg string
+	}{
+		{name: "bad IP", cidr: "10.0.1", errorMsg: "invalid IP or CIDR: 10.0.1"},
+		{name: "bad CIDR", cidr: "10.0.1.0/33", errorMsg: "invalid IP or CIDR: 10.0.1.0/33"},
+		{name: "bad pattern", cidr: "10.0.1.0/24", pattern: "master", errorMsg: "OSC address pattern must start with /"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := NewAccessList(nil).Allow(testCase.cidr, testCase.pattern)
+			if err == nil {
+				t.Fatalf("expected rule to fail")
+			}
+			if err.Error() != testCase.errorMsg {
+				t.Fatalf("failed to reject rule got '%s', expected '%s'", err.Error(), testCase.errorMsg)
+			}
+		})
+	}
+}
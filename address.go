@@ -0,0 +1,121 @@
+package osc
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+type AddressPattern struct {
+	pattern string
+	regex   *regexp.Regexp
+}
+
+// CompileAddressPattern parses an OSC address pattern supporting ?, *, [], {} and the OSC 1.1 // wildcard, each of ?, *, [] and {} is a capture
+func CompileAddressPattern(pattern string) (*AddressPattern, error) {
+	if len(pattern) == 0 || pattern[0] != '/' {
+		return nil, errors.New("OSC address pattern must start with /")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+
+	for index := 0; index < len(pattern); index++ {
+		switch char := pattern[index]; char {
+		case '*':
+			sb.WriteString("([^/]*)")
+		case '?':
+			sb.WriteString("([^/])")
+		case '[':
+			end := strings.IndexByte(pattern[index+1:], ']')
+			if end < 0 {
+				return nil, errors.New("OSC address pattern has unclosed [")
+			}
+			sb.WriteString("(" + bracketToRegex(pattern[index+1:index+1+end]) + ")")
+			index = index + 1 + end
+		case '{':
+			end := strings.IndexByte(pattern[index+1:], '}')
+			if end < 0 {
+				return nil, errors.New("OSC address pattern has unclosed {")
+			}
+			options := strings.Split(pattern[index+1:index+1+end], ",")
+			for optionIndex, option := range options {
+				options[optionIndex] = regexp.QuoteMeta(option)
+			}
+			sb.WriteString("(" + strings.Join(options, "|") + ")")
+			index = index + 1 + end
+		case '/':
+			if index+1 < len(pattern) && pattern[index+1] == '/' {
+				// NOTE: OSC 1.1 path-traversing wildcard matches any number of parts
+				sb.WriteString("(?:/[^/]*)*/")
+				index++
+			} else {
+				sb.WriteString("/")
+			}
+		case ']', '}':
+			return nil, errors.New("OSC address pattern has unexpected " + string(char))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+
+	sb.WriteString("$")
+
+	regex, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddressPattern{
+		pattern: pattern,
+		regex:   regex,
+	}, nil
+}
+
+func bracketToRegex(contents string) string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	if strings.HasPrefix(contents, "!") {
+		sb.WriteString("^/")
+		contents = contents[1:]
+	}
+	for _, char := range contents {
+		switch char {
+		case '\\', '[', ']', '^':
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(char)
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+func (p *AddressPattern) Match(address string) bool {
+	return p.regex.MatchString(address)
+}
+
+// Captures returns the part of address matched by each ?, *, [] and {} in order or nil if address doesn't match
+func (p *AddressPattern) Captures(address string) []string {
+	matches := p.regex.FindStringSubmatch(address)
+	if matches == nil {
+		return nil
+	}
+	return matches[1:]
+}
+
+// NumCaptures is the number of ?, *, [] and {} in the pattern
+func (p *AddressPattern) NumCaptures() int {
+	return p.regex.NumSubexp()
+}
+
+func (p *AddressPattern) String() string {
+	return p.pattern
+}
+
+func MatchAddress(pattern string, address string) (bool, error) {
+	addressPattern, err := CompileAddressPattern(pattern)
+	if err != nil {
+		return false, err
+	}
+	return addressPattern.Match(address), nil
+}
@@ -0,0 +1,111 @@
+package osc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGoodAddressPatternMatching(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		pattern  string
+		address  string
+		expected bool
+	}{
+		{name: "literal match", pattern: "/ch/01/mix/fader", address: "/ch/01/mix/fader", expected: true},
+		{name: "literal mismatch", pattern: "/ch/01/mix/fader", address: "/ch/02/mix/fader", expected: false},
+		{name: "literal prefix", pattern: "/ch/01", address: "/ch/01/mix/fader", expected: false},
+		{name: "star part", pattern: "/ch/*/mix/fader", address: "/ch/01/mix/fader", expected: true},
+		{name: "star does not cross parts", pattern: "/ch/*/fader", address: "/ch/01/mix/fader", expected: false},
+		{name: "star suffix", pattern: "/ch/0*", address: "/ch/07", expected: true},
+		{name: "question mark", pattern: "/ch/0?", address: "/ch/07", expected: true},
+		{name: "question mark too short", pattern: "/ch/0?", address: "/ch/0", expected: false},
+		{name: "character list", pattern: "/ch/0[123]", address: "/ch/02", expected: true},
+		{name: "character range", pattern: "/ch/0[1-3]", address: "/ch/04", expected: false},
+		{name: "negated character range", pattern: "/ch/0[!1-3]", address: "/ch/04", expected: true},
+		{name: "negated character range mismatch", pattern: "/ch/0[!1-3]", address: "/ch/02", expected: false},
+		{name: "string list", pattern: "/ch/01/{mix,eq}/on", address: "/ch/01/eq/on", expected: true},
+		{name: "string list mismatch", pattern: "/ch/01/{mix,eq}/on", address: "/ch/01/dyn/on", expected: false},
+		{name: "regex characters are literal", pattern: "/a.b", address: "/axb", expected: false},
+		{name: "path traversal", pattern: "//fader", address: "/ch/01/mix/fader", expected: true},
+		{name: "path traversal middle", pattern: "/ch//fader", address: "/ch/01/mix/fader", expected: true},
+		{name: "path traversal no parts", pattern: "/ch//fader", address: "/ch/fader", expected: true},
+		{name: "path traversal mismatch", pattern: "/bus//fader", address: "/ch/01/mix/fader", expected: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := MatchAddress(testCase.pattern, testCase.address)
+
+			if err != nil {
+				t.Fatalf("failed to compile pattern: %s", err.Error())
+			}
+
+			if got != testCase.expected {
+				t.Fatalf("pattern '%s' matching '%s' got %t, expected %t", testCase.pattern, testCase.address, got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestBadAddressPatterns(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		pattern     string
+		errorString string
+	}{
+		{name: "empty pattern", pattern: "", errorString: "OSC address pattern must start with /"},
+		{name: "no leading slash", pattern: "ch/01", errorString: "OSC address pattern must start with /"},
+		{name: "unclosed bracket", pattern: "/ch/[12", errorString: "OSC address pattern has unclosed ["},
+		{name: "unclosed brace", pattern: "/ch/{mix,eq", errorString: "OSC address pattern has unclosed {"},
+		{name: "unexpected brace", pattern: "/ch/mix}", errorString: "OSC address pattern has unexpected }"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := CompileAddressPattern(testCase.pattern)
+
+			if err == nil {
+				t.Fatalf("CompileAddressPattern expected to fail but got: %+v", got)
+			}
+
+			if err.Error() != testCase.errorString {
+				t.Fatalf("CompileAddressPattern got error '%s', expected '%s'", err.Error(), testCase.errorString)
+			}
+		})
+	}
+}
+
+func TestAddressPatternCaptures(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		pattern  string
+		address  string
+		expected []string
+	}{
+		{name: "literal", pattern: "/ch/01/mix/fader", address: "/ch/01/mix/fader", expected: []string{}},
+		{name: "star", pattern: "/ch/*/mix/fader", address: "/ch/01/mix/fader", expected: []string{"01"}},
+		{name: "star suffix", pattern: "/ch/0*", address: "/ch/07", expected: []string{"7"}},
+		{name: "question mark and character list", pattern: "/ch/?[123]", address: "/ch/02", expected: []string{"0", "2"}},
+		{name: "string list", pattern: "/ch/*/{mix,eq}/on", address: "/ch/01/eq/on", expected: []string{"01", "eq"}},
+		{name: "path traversal is not a capture", pattern: "//*/fader", address: "/ch/01/mix/fader", expected: []string{"mix"}},
+		{name: "mismatch", pattern: "/ch/*/mix/fader", address: "/bus/01/mix/fader", expected: nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			addressPattern, err := CompileAddressPattern(testCase.pattern)
+			if err != nil {
+				t.Fatalf("failed to compile pattern: %s", err.Error())
+			}
+
+			got := addressPattern.Captures(testCase.address)
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to capture got '%v', expected '%v'", got, testCase.expected)
+			}
+		})
+	}
+}
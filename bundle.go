@@ -2,8 +2,12 @@ package osc
 
 import (
 	"errors"
+	"fmt"
 )
 
+// bundle header is #bundle and the time tag, each element is prefixed by its size
+const bundleHeaderSize = 16
+
 func (b *OSCBundle) ToBytes() ([]byte, error) {
 
 	bytes := stringToOSCBytes("#bundle")
@@ -107,3 +111,103 @@ func BundleFromBytes(bytes []byte) (*OSCBundle, []byte, error) {
 		nil
 
 }
+
+// SplitBundle splits bundle into bundles that each encode to at most maxSize bytes, keeping the time tag and the order of the contents
+func SplitBundle(bundle *OSCBundle, maxSize int) ([]*OSCBundle, error) {
+	if maxSize < bundleHeaderSize {
+		return nil, fmt.Errorf("OSC bundle size limit %d is smaller than the %d byte bundle header", maxSize, bundleHeaderSize)
+	}
+
+	bundles := []*OSCBundle{}
+	current := &OSCBundle{TimeTag: bundle.TimeTag, Contents: []OSCPacket{}}
+	size := bundleHeaderSize
+
+	add := func(packet OSCPacket, packetSize int) {
+		if len(current.Contents) > 0 && size+4+packetSize > maxSize {
+			bundles = append(bundles, current)
+			current = &OSCBundle{TimeTag: bundle.TimeTag, Contents: []OSCPacket{}}
+			size = bundleHeaderSize
+		}
+		current.Contents = append(current.Contents, packet)
+		size += 4 + packetSize
+	}
+
+	for _, content := range bundle.Contents {
+		contentBytes, err := content.ToBytes()
+		if err != nil {
+			return nil, err
+		}
+		if bundleHeaderSize+4+len(contentBytes) <= maxSize {
+			add(content, len(contentBytes))
+			continue
+		}
+
+		nested, ok := content.(*OSCBundle)
+		if !ok {
+			return nil, packetTooBigError(content, len(contentBytes), maxSize-bundleHeaderSize-4)
+		}
+		// NOTE: a nested bundle that is too big becomes several nested bundles with the same time tag
+		split, err := SplitBundle(nested, maxSize-bundleHeaderSize-4)
+		if err != nil {
+			return nil, err
+		}
+		for _, splitBundle := range split {
+			splitBytes, err := splitBundle.ToBytes()
+			if err != nil {
+				return nil, err
+			}
+			add(splitBundle, len(splitBytes))
+		}
+	}
+	return append(bundles, current), nil
+}
+
+func packetTooBigError(packet OSCPacket, size int, maxSize int) error {
+	if message, ok := packet.(*OSCMessage); ok {
+		return fmt.Errorf("OSC message %s is %d bytes which is over the %d byte limit", message.Address, size, maxSize)
+	}
+	return fmt.Errorf("OSC packet is %d bytes which is over the %d byte limit", size, maxSize)
+}
+
+// pruneMessages returns packet with the messages keep rejects removed, bundles left empty are removed too and nil means nothing is left
+func pruneMessages(packet OSCPacket, keep func(message *OSCMessage) bool) OSCPacket {
+	return rebuildPacket(packet, func(packet OSCPacket) (OSCPacket, bool) {
+		message, ok := packet.(*OSCMessage)
+		if !ok {
+			return nil, false
+		}
+		if keep(message) {
+			return message, true
+		}
+		return nil, true
+	})
+}
+
+// rebuildPacket replaces each packet visit handles with what it returns, nil removes it, and rebuilds the bundles around them
+//
+// Bundles visit doesn't handle have their contents rebuilt in turn, keeping their time tag, and are removed if left empty.
+// Anything else visit doesn't handle is removed so a check can't be skipped by an unexpected packet type.
+func rebuildPacket(packet OSCPacket, visit func(packet OSCPacket) (OSCPacket, bool)) OSCPacket {
+	packet = unwrapPacket(packet)
+	if rebuilt, handled := visit(packet); handled {
+		return rebuilt
+	}
+
+	bundle, ok := packet.(*OSCBundle)
+	if !ok {
+		return nil
+	}
+	contents := []OSCPacket{}
+	for _, content := range bundle.Contents {
+		if rebuilt := rebuildPacket(content, visit); rebuilt != nil {
+			contents = append(contents, rebuilt)
+		}
+	}
+	if len(contents) == 0 {
+		return nil
+	}
+	return &OSCBundle{
+		TimeTag:  bundle.TimeTag,
+		Contents: contents,
+	}
+}
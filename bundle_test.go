@@ -2,6 +2,7 @@ package osc
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -238,3 +239,171 @@ func FuzzBundleFromBytes(f *testing.F) {
 		_, _, _ = BundleFromBytes(data)
 	})
 }
+
+func TestSplitBundle(t *testing.T) {
+	timeTag := OSCTimeTag{seconds: 32, fractionalSeconds: 0}
+	// NOTE: each of these encodes to 12 bytes so is 16 bytes in a bundle
+	a := &OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: int32(1)}}}
+	b := &OSCMessage{Address: "/b", Args: []OSCArg{{Type: "i", Value: int32(2)}}}
+	c := &OSCMessage{Address: "/c", Args: []OSCArg{{Type: "i", Value: int32(3)}}}
+	nestedTimeTag := OSCTimeTag{seconds: 64, fractionalSeconds: 0}
+
+	testCases := []struct {
+		name     string
+		bundle   *OSCBundle
+		maxSize  int
+		expected []*OSCBundle
+	}{
+		{
+			name:    "fits",
+			bundle:  &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a, b, c}},
+			maxSize: 64,
+			expected: []*OSCBundle{
+				{TimeTag: timeTag, Contents: []OSCPacket{a, b, c}},
+			},
+		},
+		{
+			name:    "split in order",
+			bundle:  &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a, b, c}},
+			maxSize: 48,
+			expected: []*OSCBundle{
+				{TimeTag: timeTag, Contents: []OSCPacket{a, b}},
+				{TimeTag: timeTag, Contents: []OSCPacket{c}},
+			},
+		},
+		{
+			name:    "one message per bundle",
+			bundle:  &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a, b, c}},
+			maxSize: 32,
+			expected: []*OSCBundle{
+				{TimeTag: timeTag, Contents: []OSCPacket{a}},
+				{TimeTag: timeTag, Contents: []OSCPacket{b}},
+				{TimeTag: timeTag, Contents: []OSCPacket{c}},
+			},
+		},
+		{
+			name: "nested bundle is split",
+			bundle: &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{
+				a,
+				&OSCBundle{TimeTag: nestedTimeTag, Contents: []OSCPacket{b, c}},
+			}},
+			maxSize: 52,
+			expected: []*OSCBundle{
+				{TimeTag: timeTag, Contents: []OSCPacket{a}},
+				{TimeTag: timeTag, Contents: []OSCPacket{&OSCBundle{TimeTag: nestedTimeTag, Contents: []OSCPacket{b}}}},
+				{TimeTag: timeTag, Contents: []OSCPacket{&OSCBundle{TimeTag: nestedTimeTag, Contents: []OSCPacket{c}}}},
+			},
+		},
+		{
+			name:    "empty",
+			bundle:  &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{}},
+			maxSize: 16,
+			expected: []*OSCBundle{
+				{TimeTag: timeTag, Contents: []OSCPacket{}},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := SplitBundle(testCase.bundle, testCase.maxSize)
+			if err != nil {
+				t.Fatalf("failed to split bundle: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to split bundle got '%v', expected '%v'", got, testCase.expected)
+			}
+
+			for _, bundle := range got {
+				bundleBytes, err := bundle.ToBytes()
+				if err != nil {
+					t.Fatalf("failed to encode split bundle: %s", err.Error())
+				}
+				if len(bundleBytes) > testCase.maxSize {
+					t.Fatalf("failed to keep bundle under the limit got %d bytes, expected at most %d", len(bundleBytes), testCase.maxSize)
+				}
+			}
+		})
+	}
+}
+
+func TestBadSplitBundle(t *testing.T) {
+	timeTag := OSCTimeTag{seconds: 32, fractionalSeconds: 0}
+	a := &OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: int32(1)}}}
+
+	testCases := []struct {
+		name     string
+		bundle   *OSCBundle
+		maxSize  int
+		errorMsg string
+	}{
+		{
+			name:     "limit smaller than header",
+			bundle:   &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a}},
+			maxSize:  8,
+			errorMsg: "OSC bundle size limit 8 is smaller than the 16 byte bundle header",
+		},
+		{
+			name:     "message can't fit",
+			bundle:   &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a}},
+			maxSize:  24,
+			errorMsg: "OSC message /a is 12 bytes which is over the 4 byte limit",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := SplitBundle(testCase.bundle, testCase.maxSize)
+			if err == nil {
+				t.Fatalf("expected split to fail")
+			}
+			if err.Error() != testCase.errorMsg {
+				t.Fatalf("failed to reject split got '%s', expected '%s'", err.Error(), testCase.errorMsg)
+			}
+		})
+	}
+}
+
+func TestPruneMessages(t *testing.T) {
+	a := &OSCMessage{Address: "/keep/a", Args: []OSCArg{}}
+	b := &OSCMessage{Address: "/drop/b", Args: []OSCArg{}}
+	c := &OSCMessage{Address: "/keep/c", Args: []OSCArg{}}
+	timeTag := NewOSCTimeTag(3000000000, 0)
+
+	testCases := []struct {
+		name     string
+		packet   OSCPacket
+		expected OSCPacket
+	}{
+		{name: "kept message", packet: a, expected: a},
+		{name: "dropped message", packet: b, expected: nil},
+		{
+			name:     "bundle keeps its time tag",
+			packet:   &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a, b, c}},
+			expected: &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a, c}},
+		},
+		{
+			name:     "empty nested bundle is removed",
+			packet:   &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a, &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{b}}}},
+			expected: &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a}},
+		},
+		{
+			name:     "bundle with nothing left",
+			packet:   &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{b}},
+			expected: nil,
+		},
+		{name: "encoded packet is checked", packet: encodedPacket{packet: b}, expected: nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := pruneMessages(testCase.packet, func(message *OSCMessage) bool {
+				return strings.HasPrefix(message.Address, "/keep/")
+			})
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to prune messages got '%v', expected '%v'", got, testCase.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package osc
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultBundleSize keeps a bundle in one UDP datagram on an Ethernet network, 1500 byte MTU less the IPv4 and UDP headers
+const DefaultBundleSize = 1472
+
+// Bundler collects packets written to it and sends them on as a single OSCBundle
+type Bundler struct {
+	// Window is how long after the first packet is queued the bundle is sent, zero only sends on Flush or when MaxSize is reached
+	Window time.Duration
+	// MaxSize is the most bytes an encoded bundle can be before it is sent, zero has no limit
+	MaxSize int
+	// TimeTag is called for each bundle sent, nil uses an immediate time tag
+	TimeTag func() OSCTimeTag
+	// ErrorHandler is called with errors sending a bundle when the Window ends, nil ignores them
+	ErrorHandler func(err error)
+
+	writer   PacketWriter
+	contents []OSCPacket
+	size     int
+	batch    int
+	timer    *time.Timer
+	mutex    sync.Mutex
+}
+
+// NewBundler bundles packets bound for writer, with no Window they are only sent on Flush or when DefaultBundleSize is reached
+func NewBundler(writer PacketWriter, window time.Duration) *Bundler {
+	return &Bundler{
+		Window:   window,
+		MaxSize:  DefaultBundleSize,
+		writer:   writer,
+		contents: []OSCPacket{},
+	}
+}
+
+// WritePacket queues packet, it sends the queued bundle first if adding packet would go over MaxSize
+func (b *Bundler) WritePacket(packet OSCPacket) error {
+	packetBytes, err := packet.ToBytes()
+	if err != nil {
+		return err
+	}
+	elementSize := 4 + len(packetBytes)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.MaxSize > 0 && len(b.contents) > 0 && b.size+elementSize > b.MaxSize {
+		if err := b.flush(); err != nil {
+			return err
+		}
+	}
+
+	// NOTE: a packet too big to share a bundle goes out on its own
+	if b.MaxSize > 0 && bundleHeaderSize+elementSize > b.MaxSize {
+		return b.writer.WritePacket(packet)
+	}
+
+	if len(b.contents) == 0 {
+		b.size = bundleHeaderSize
+		if b.Window > 0 {
+			batch := b.batch
+			b.timer = time.AfterFunc(b.Window, func() {
+				b.windowEnded(batch)
+			})
+		}
+	}
+	b.contents = append(b.contents, packet)
+	b.size += elementSize
+	return nil
+}
+
+// Flush sends the queued packets now
+func (b *Bundler) Flush() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.flush()
+}
+
+// Close sends the queued packets, the writer is not closed
+func (b *Bundler) Close() error {
+	return b.Flush()
+}
+
+// Len is the number of packets waiting to be sent
+func (b *Bundler) Len() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.contents)
+}
+
+func (b *Bundler) windowEnded(batch int) {
+	b.mutex.Lock()
+	// NOTE: the bundle this timer was for may have already gone out because it filled up
+	if batch != b.batch {
+		b.mutex.Unlock()
+		return
+	}
+	err := b.flush()
+	b.mutex.Unlock()
+
+	if err != nil && b.ErrorHandler != nil {
+		b.ErrorHandler(err)
+	}
+}
+
+func (b *Bundler) flush() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.contents) == 0 {
+		return nil
+	}
+
+	timeTag := ImmediateTimeTag()
+	if b.TimeTag != nil {
+		timeTag = b.TimeTag()
+	}
+	bundle := &OSCBundle{
+		TimeTag:  timeTag,
+		Contents: b.contents,
+	}
+	b.contents = []OSCPacket{}
+	b.size = 0
+	b.batch++
+	return b.writer.WritePacket(bundle)
+}
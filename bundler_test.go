@@ -0,0 +1,141 @@
+package osc
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingWriter struct {
+	packets []OSCPacket
+	written chan struct{}
+	mutex   sync.Mutex
+}
+
+func newRecordingWriter() *recordingWriter {
+	return &recordingWriter{
+		packets: []OSCPacket{},
+		written: make(chan struct{}, 16),
+	}
+}
+
+func (w *recordingWriter) WritePacket(packet OSCPacket) error {
+	w.mutex.Lock()
+	w.packets = append(w.packets, packet)
+	w.mutex.Unlock()
+	w.written <- struct{}{}
+	return nil
+}
+
+func (w *recordingWriter) Packets() []OSCPacket {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.packets
+}
+
+func TestBundlerSize(t *testing.T) {
+	// NOTE: each of these encodes to 12 bytes so is 16 bytes in a bundle
+	messages := []OSCPacket{
+		&OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: int32(1)}}},
+		&OSCMessage{Address: "/b", Args: []OSCArg{{Type: "i", Value: int32(2)}}},
+		&OSCMessage{Address: "/c", Args: []OSCArg{{Type: "i", Value: int32(3)}}},
+	}
+	big := &OSCMessage{Address: "/big", Args: []OSCArg{{Type: "b", Value: make([]byte, 64)}}}
+
+	testCases := []struct {
+		name     string
+		maxSize  int
+		packets  []OSCPacket
+		expected []OSCPacket
+	}{
+		{
+			name:    "everything fits",
+			maxSize: 0,
+			packets: messages,
+			expected: []OSCPacket{
+				&OSCBundle{TimeTag: ImmediateTimeTag(), Contents: messages},
+			},
+		},
+		{
+			name:    "split at the budget",
+			maxSize: 48,
+			packets: messages,
+			expected: []OSCPacket{
+				&OSCBundle{TimeTag: ImmediateTimeTag(), Contents: messages[0:2]},
+				&OSCBundle{TimeTag: ImmediateTimeTag(), Contents: messages[2:3]},
+			},
+		},
+		{
+			name:    "too big goes out alone",
+			maxSize: 48,
+			packets: []OSCPacket{messages[0], big, messages[1]},
+			expected: []OSCPacket{
+				&OSCBundle{TimeTag: ImmediateTimeTag(), Contents: messages[0:1]},
+				big,
+				&OSCBundle{TimeTag: ImmediateTimeTag(), Contents: messages[1:2]},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			writer := newRecordingWriter()
+			bundler := NewBundler(writer, 0)
+			bundler.MaxSize = testCase.maxSize
+
+			for _, packet := range testCase.packets {
+				err := bundler.WritePacket(packet)
+				if err != nil {
+					t.Fatalf("failed to queue packet: %s", err.Error())
+				}
+			}
+			err := bundler.Flush()
+			if err != nil {
+				t.Fatalf("failed to flush: %s", err.Error())
+			}
+
+			got := writer.Packets()
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to bundle got '%v', expected '%v'", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestBundlerWindow(t *testing.T) {
+	writer := newRecordingWriter()
+	bundler := NewBundler(writer, 20*time.Millisecond)
+	timeTag := TimeTagFromTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	bundler.TimeTag = func() OSCTimeTag {
+		return timeTag
+	}
+
+	message := &OSCMessage{Address: "/frame", Args: []OSCArg{}}
+	for index := 0; index < 3; index++ {
+		err := bundler.WritePacket(message)
+		if err != nil {
+			t.Fatalf("failed to queue packet: %s", err.Error())
+		}
+	}
+	if bundler.Len() != 3 {
+		t.Fatalf("failed to queue packets got %d, expected 3", bundler.Len())
+	}
+
+	select {
+	case <-writer.written:
+	case <-time.After(time.Second):
+		t.Fatalf("failed to send bundle when the window ended")
+	}
+
+	expected := []OSCPacket{
+		&OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{message, message, message}},
+	}
+	got := writer.Packets()
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("failed to bundle got '%v', expected '%v'", got, expected)
+	}
+	if bundler.Len() != 0 {
+		t.Fatalf("failed to empty the queue got %d, expected 0", bundler.Len())
+	}
+}
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"github.com/jwetzell/osc-go/internal/commands"
+)
+
+// NOTE: kept for compatibility, this is the same as osc make
+func main() {
+	cmd := commands.Make()
+	cmd.Name = "makeosc"
+	cmd.Aliases = nil
+	commands.Run(cmd)
+}
@@ -0,0 +1,9 @@
+package main
+
+import (
+	"github.com/jwetzell/osc-go/internal/commands"
+)
+
+func main() {
+	commands.Run(commands.Root())
+}
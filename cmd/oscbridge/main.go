@@ -0,0 +1,12 @@
+package main
+
+import (
+	"github.com/jwetzell/osc-go/internal/commands"
+)
+
+// NOTE: the same as osc bridge
+func main() {
+	cmd := commands.Bridge()
+	cmd.Name = "oscbridge"
+	commands.Run(cmd)
+}
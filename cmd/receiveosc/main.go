@@ -0,0 +1,13 @@
+package main
+
+import (
+	"github.com/jwetzell/osc-go/internal/commands"
+)
+
+// NOTE: kept for compatibility, this is the same as osc recv
+func main() {
+	cmd := commands.Receive()
+	cmd.Name = "receiveosc"
+	cmd.Aliases = nil
+	commands.Run(cmd)
+}
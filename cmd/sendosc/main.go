@@ -0,0 +1,13 @@
+package main
+
+import (
+	"github.com/jwetzell/osc-go/internal/commands"
+)
+
+// NOTE: kept for compatibility, this is the same as osc send
+func main() {
+	cmd := commands.Send()
+	cmd.Name = "sendosc"
+	cmd.Aliases = nil
+	commands.Run(cmd)
+}
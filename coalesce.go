@@ -0,0 +1,146 @@
+package osc
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// Coalescer is a queue that keeps only the latest message for each address, addresses come out in the order they were first queued
+type Coalescer struct {
+	// ErrorHandler is called with errors writing a message in Drain, which carries on with the next one, nil ignores them
+	ErrorHandler func(err error)
+
+	order   []string
+	latest  map[string]*OSCMessage
+	dropped int
+	closed  bool
+	ready   chan struct{}
+	mutex   sync.Mutex
+}
+
+func NewCoalescer() *Coalescer {
+	return &Coalescer{
+		order:  []string{},
+		latest: map[string]*OSCMessage{},
+		ready:  make(chan struct{}, 1),
+	}
+}
+
+// Push queues message, replacing a queued message with the same address but keeping its place in line
+func (c *Coalescer) Push(message *OSCMessage) {
+	c.mutex.Lock()
+	if _, ok := c.latest[message.Address]; ok {
+		c.dropped++
+	} else {
+		c.order = append(c.order, message.Address)
+	}
+	c.latest[message.Address] = message
+	c.mutex.Unlock()
+
+	c.signal()
+}
+
+// Pop removes the message for the address that has been waiting longest
+func (c *Coalescer) Pop() (*OSCMessage, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.order) == 0 {
+		return nil, false
+	}
+	address := c.order[0]
+	c.order = c.order[1:]
+	message := c.latest[address]
+	delete(c.latest, address)
+	return message, true
+}
+
+// Len is the number of addresses waiting
+func (c *Coalescer) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.order)
+}
+
+// Dropped is the number of stale messages replaced before they were drained
+func (c *Coalescer) Dropped() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.dropped
+}
+
+// WritePacket queues every message in packet, bundles are flattened and their time tags are not kept
+func (c *Coalescer) WritePacket(packet OSCPacket) error {
+	switch packet := packet.(type) {
+	case *OSCMessage:
+		c.Push(packet)
+	case *OSCBundle:
+		for _, content := range packet.Contents {
+			c.WritePacket(content)
+		}
+	}
+	return nil
+}
+
+// ServeOSC queues received messages so a Coalescer can sit in front of a slow Handler
+func (c *Coalescer) ServeOSC(w PacketWriter, packet OSCPacket, source net.Addr) {
+	c.WritePacket(packet)
+}
+
+// Close makes Drain return once the queue is empty
+func (c *Coalescer) Close() error {
+	c.mutex.Lock()
+	c.closed = true
+	c.mutex.Unlock()
+
+	c.signal()
+	return nil
+}
+
+// Drain writes queued messages to w, waiting interval after each one, until ctx is done or the Coalescer is closed and empty
+func (c *Coalescer) Drain(ctx context.Context, w PacketWriter, interval time.Duration) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		message, ok := c.Pop()
+		if !ok {
+			c.mutex.Lock()
+			closed := c.closed
+			c.mutex.Unlock()
+			if closed {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.ready:
+			}
+			continue
+		}
+
+		// NOTE: a failed write drops that message, the destination may be back for the next one
+		if err := w.WritePacket(message); err != nil && c.ErrorHandler != nil {
+			c.ErrorHandler(err)
+		}
+
+		if interval > 0 {
+			timer.Reset(interval)
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+func (c *Coalescer) signal() {
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,162 @@
+package osc
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCoalescerLatestValue(t *testing.T) {
+	fader := func(channel string, value float32) *OSCMessage {
+		return &OSCMessage{Address: "/ch/" + channel + "/fader", Args: []OSCArg{{Type: "f", Value: value}}}
+	}
+
+	testCases := []struct {
+		name     string
+		pushed   []OSCPacket
+		expected []*OSCMessage
+		dropped  int
+	}{
+		{
+			name:     "latest value wins",
+			pushed:   []OSCPacket{fader("1", 0.1), fader("1", 0.2), fader("1", 0.3)},
+			expected: []*OSCMessage{fader("1", 0.3)},
+			dropped:  2,
+		},
+		{
+			name:     "first seen order is kept",
+			pushed:   []OSCPacket{fader("1", 0.1), fader("2", 0.5), fader("1", 0.2), fader("3", 0.9)},
+			expected: []*OSCMessage{fader("1", 0.2), fader("2", 0.5), fader("3", 0.9)},
+			dropped:  1,
+		},
+		{
+			name: "bundles are flattened",
+			pushed: []OSCPacket{
+				&OSCBundle{TimeTag: ImmediateTimeTag(), Contents: []OSCPacket{fader("1", 0.1), fader("2", 0.5)}},
+				fader("2", 0.6),
+			},
+			expected: []*OSCMessage{fader("1", 0.1), fader("2", 0.6)},
+			dropped:  1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			coalescer := NewCoalescer()
+			for _, packet := range testCase.pushed {
+				coalescer.WritePacket(packet)
+			}
+
+			got := []*OSCMessage{}
+			for {
+				message, ok := coalescer.Pop()
+				if !ok {
+					break
+				}
+				got = append(got, message)
+			}
+
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to coalesce got '%v', expected '%v'", got, testCase.expected)
+			}
+			if coalescer.Dropped() != testCase.dropped {
+				t.Fatalf("failed to count dropped got %d, expected %d", coalescer.Dropped(), testCase.dropped)
+			}
+		})
+	}
+}
+
+func TestCoalescerDrain(t *testing.T) {
+	coalescer := NewCoalescer()
+	writer := newRecordingWriter()
+
+	done := make(chan struct{})
+	go func() {
+		coalescer.Drain(context.Background(), writer, 10*time.Millisecond)
+		close(done)
+	}()
+
+	first := &OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: int32(1)}}}
+	coalescer.Push(first)
+	select {
+	case <-writer.written:
+	case <-time.After(time.Second):
+		t.Fatalf("failed to drain pushed message")
+	}
+
+	// NOTE: these arrive while the drain is waiting out its interval so only the last is written
+	for index := int32(2); index <= 5; index++ {
+		coalescer.Push(&OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: index}}})
+	}
+	coalescer.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("failed to stop draining after close")
+	}
+
+	expected := []OSCPacket{first, &OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: int32(5)}}}}
+	got := writer.Packets()
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("failed to drain latest values got '%v', expected '%v'", got, expected)
+	}
+}
+
+type failingWriter struct {
+	failures int
+	written  *recordingWriter
+}
+
+func (w *failingWriter) WritePacket(packet OSCPacket) error {
+	if w.failures > 0 {
+		w.failures--
+		return errors.New("connection refused")
+	}
+	return w.written.WritePacket(packet)
+}
+
+func TestCoalescerDrainWriteError(t *testing.T) {
+	coalescer := NewCoalescer()
+	writer := &failingWriter{failures: 1, written: newRecordingWriter()}
+	errs := make(chan error, 1)
+	coalescer.ErrorHandler = func(err error) {
+		errs <- err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		coalescer.Drain(context.Background(), writer, 0)
+		close(done)
+	}()
+
+	coalescer.Push(&OSCMessage{Address: "/a", Args: []OSCArg{}})
+	select {
+	case err := <-errs:
+		if err.Error() != "connection refused" {
+			t.Fatalf("failed to report write error got '%s', expected '%s'", err.Error(), "connection refused")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("failed to report write error")
+	}
+
+	second := &OSCMessage{Address: "/b", Args: []OSCArg{}}
+	coalescer.Push(second)
+	select {
+	case <-writer.written.written:
+	case <-time.After(time.Second):
+		t.Fatalf("failed to keep draining after a write error")
+	}
+
+	coalescer.Close()
+	<-done
+	if coalescer.Len() != 0 {
+		t.Fatalf("failed to drain queue got %d waiting, expected 0", coalescer.Len())
+	}
+	expected := []OSCPacket{second}
+	if !reflect.DeepEqual(writer.written.Packets(), expected) {
+		t.Fatalf("failed to drain after error got '%v', expected '%v'", writer.written.Packets(), expected)
+	}
+}
@@ -0,0 +1,104 @@
+package osc
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Conn reads and writes whole OSC packets over some transport
+type Conn interface {
+	ReadPacket() (OSCPacket, net.Addr, error)
+	WritePacket(packet OSCPacket) error
+	Close() error
+}
+
+// PacketConn is a Conn that can send to any address, like an unconnected UDP socket
+type PacketConn interface {
+	Conn
+	WritePacketTo(packet OSCPacket, addr net.Addr) error
+}
+
+// DecodeError is returned by ReadPacket when data arrived that was not a valid OSC packet, the Conn is still usable
+type DecodeError struct {
+	Bytes []byte
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("malformed OSC packet: %s", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+type datagramConn interface {
+	net.Conn
+	ReadFrom(b []byte) (int, net.Addr, error)
+	WriteTo(b []byte, addr net.Addr) (int, error)
+}
+
+func readDatagramPacket(conn datagramConn, buffer []byte) (OSCPacket, net.Addr, error) {
+	bytesRead, remoteAddr, err := conn.ReadFrom(buffer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// NOTE: decoded blobs point into the bytes they were read from so the buffer can't be reused
+	packetBytes := make([]byte, bytesRead)
+	copy(packetBytes, buffer[0:bytesRead])
+
+	packet, _, err := PacketFromBytes(packetBytes)
+	if err != nil {
+		return nil, remoteAddr, &DecodeError{Bytes: packetBytes, Err: err}
+	}
+	return packet, remoteAddr, nil
+}
+
+func writeDatagramPacket(conn datagramConn, packet OSCPacket) error {
+	if conn.RemoteAddr() == nil {
+		return errors.New("datagram connection has no remote address, use WritePacketTo")
+	}
+	packetBytes, err := packet.ToBytes()
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(packetBytes)
+	return err
+}
+
+func writeDatagramPacketTo(conn datagramConn, packet OSCPacket, addr net.Addr) error {
+	packetBytes, err := packet.ToBytes()
+	if err != nil {
+		return err
+	}
+	_, err = conn.WriteTo(packetBytes, addr)
+	return err
+}
+
+// Dial connects to an OSC endpoint over udp, unixgram, tcp or unix, framing only applies to the stream networks
+func Dial(network string, address string, framing Framing) (Conn, error) {
+	switch network {
+	case "udp", "udp4", "udp6":
+		conn, err := DialUDP(network, address)
+		if err != nil {
+			return nil, err
+		}
+		return conn, nil
+	case "unixgram":
+		conn, err := DialUnixgram("", address)
+		if err != nil {
+			return nil, err
+		}
+		return conn, nil
+	case "tcp", "tcp4", "tcp6", "unix":
+		conn, err := DialStream(network, address, framing)
+		if err != nil {
+			return nil, err
+		}
+		return conn, nil
+	default:
+		return nil, fmt.Errorf("unsupported network: %s", network)
+	}
+}
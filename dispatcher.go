@@ -0,0 +1,73 @@
+package osc
+
+import (
+	"net"
+	"strings"
+	"sync"
+)
+
+type MessageHandlerFunc func(w PacketWriter, message *OSCMessage, source net.Addr)
+
+type dispatcherRoute struct {
+	pattern *AddressPattern
+	handler MessageHandlerFunc
+}
+
+// Dispatcher is a Handler that routes each message, including those inside bundles, to every handler whose pattern matches
+type Dispatcher struct {
+	routes []dispatcherRoute
+	mutex  sync.RWMutex
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		routes: []dispatcherRoute{},
+	}
+}
+
+// Handle registers handler for messages with an address matching the OSC address pattern
+func (d *Dispatcher) Handle(pattern string, handler MessageHandlerFunc) error {
+	addressPattern, err := CompileAddressPattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.routes = append(d.routes, dispatcherRoute{
+		pattern: addressPattern,
+		handler: handler,
+	})
+	return nil
+}
+
+func (d *Dispatcher) ServeOSC(w PacketWriter, packet OSCPacket, source net.Addr) {
+	switch packet := packet.(type) {
+	case *OSCBundle:
+		// TODO: schedule bundles with a future time tag instead of dispatching immediately
+		for _, content := range packet.Contents {
+			d.ServeOSC(w, content, source)
+		}
+	case *OSCMessage:
+		d.dispatch(w, packet, source)
+	}
+}
+
+func (d *Dispatcher) dispatch(w PacketWriter, message *OSCMessage, source net.Addr) {
+	d.mutex.RLock()
+	routes := d.routes
+	d.mutex.RUnlock()
+
+	// NOTE: per the OSC spec the incoming address may itself be a pattern
+	var messagePattern *AddressPattern
+	if strings.ContainsAny(message.Address, "*?[]{}") || strings.Contains(message.Address, "//") {
+		messagePattern, _ = CompileAddressPattern(message.Address)
+	}
+
+	for _, route := range routes {
+		if route.pattern.Match(message.Address) || (messagePattern != nil && messagePattern.Match(route.pattern.String())) {
+			route.handler(w, message, source)
+		}
+	}
+}
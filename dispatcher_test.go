@@ -0,0 +1,77 @@
+package osc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestDispatcherRouting(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		packet   OSCPacket
+		expected []string
+	}{
+		{
+			name:     "literal address",
+			packet:   &OSCMessage{Address: "/ch/01/mix/fader"},
+			expected: []string{"/ch/*/mix/fader:/ch/01/mix/fader", "//fader:/ch/01/mix/fader"},
+		},
+		{
+			name:     "no matching route",
+			packet:   &OSCMessage{Address: "/bus/01/mix/on"},
+			expected: []string{},
+		},
+		{
+			name:     "incoming address pattern",
+			packet:   &OSCMessage{Address: "/main/{st,m}/mix/on"},
+			expected: []string{"/main/st/mix/on:/main/{st,m}/mix/on"},
+		},
+		{
+			name: "bundle contents",
+			packet: &OSCBundle{
+				TimeTag: ImmediateTimeTag(),
+				Contents: []OSCPacket{
+					&OSCMessage{Address: "/main/st/mix/on"},
+					&OSCBundle{Contents: []OSCPacket{&OSCMessage{Address: "/ch/02/mix/fader"}}},
+				},
+			},
+			expected: []string{
+				"/main/st/mix/on:/main/st/mix/on",
+				"/ch/*/mix/fader:/ch/02/mix/fader",
+				"//fader:/ch/02/mix/fader",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := []string{}
+
+			dispatcher := NewDispatcher()
+			for _, pattern := range []string{"/ch/*/mix/fader", "//fader", "/main/st/mix/on"} {
+				err := dispatcher.Handle(pattern, func(w PacketWriter, message *OSCMessage, source net.Addr) {
+					got = append(got, pattern+":"+message.Address)
+				})
+				if err != nil {
+					t.Fatalf("failed to register handler: %s", err.Error())
+				}
+			}
+
+			dispatcher.ServeOSC(nil, testCase.packet, nil)
+
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to dispatch properly got '%v', expected '%v'", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestDispatcherBadPattern(t *testing.T) {
+	dispatcher := NewDispatcher()
+	err := dispatcher.Handle("no/slash", func(w PacketWriter, message *OSCMessage, source net.Addr) {})
+	if err == nil {
+		t.Fatalf("Handle expected to fail with a bad pattern")
+	}
+}
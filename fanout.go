@@ -0,0 +1,200 @@
+package osc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// encodedPacket carries bytes that were already encoded so every Conn written to doesn't encode the packet again
+type encodedPacket struct {
+	packet OSCPacket
+	bytes  []byte
+}
+
+func (p encodedPacket) ToBytes() ([]byte, error) {
+	return p.bytes, nil
+}
+
+func (p encodedPacket) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.packet)
+}
+
+// unwrapPacket returns the packet an encodedPacket was made from so Conns can look inside it
+func unwrapPacket(packet OSCPacket) OSCPacket {
+	if encoded, ok := packet.(encodedPacket); ok {
+		return encoded.packet
+	}
+	return packet
+}
+
+// TargetStats counts the writes to one FanOut target
+type TargetStats struct {
+	Name         string
+	Sent         int
+	Failed       int
+	LastError    error
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// AverageLatency is the mean time a write to the target took
+func (s TargetStats) AverageLatency() time.Duration {
+	writes := s.Sent + s.Failed
+	if writes == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(writes)
+}
+
+// DefaultFanOutWriteTimeout is how long a FanOut waits for a target by default
+const DefaultFanOutWriteTimeout = time.Second
+
+type fanOutTarget struct {
+	conn  Conn
+	stats TargetStats
+	// NOTE: stuck is set while a write that timed out is still going so later packets skip the target instead of piling up behind it
+	stuck bool
+}
+
+// FanOut writes each packet to many Conns concurrently after encoding it once, a failing target doesn't stop the others
+type FanOut struct {
+	// WriteTimeout is how long WritePacket waits for a target before counting the write as failed, zero waits for every target
+	WriteTimeout time.Duration
+
+	targets []*fanOutTarget
+	mutex   sync.Mutex
+}
+
+func NewFanOut() *FanOut {
+	return &FanOut{
+		WriteTimeout: DefaultFanOutWriteTimeout,
+		targets:      []*fanOutTarget{},
+	}
+}
+
+// Add writes future packets to conn as well, name identifies the target in stats and errors
+func (f *FanOut) Add(name string, conn Conn) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.targets = append(f.targets, &fanOutTarget{
+		conn:  conn,
+		stats: TargetStats{Name: name},
+	})
+}
+
+// WritePacket writes packet to every target and waits for them all up to WriteTimeout, the error names each target that failed
+//
+// A target still stuck on a write that timed out is skipped and counted as failed until that write returns.
+func (f *FanOut) WritePacket(packet OSCPacket) error {
+	packetBytes, err := packet.ToBytes()
+	if err != nil {
+		return err
+	}
+	encoded := encodedPacket{packet: packet, bytes: packetBytes}
+
+	f.mutex.Lock()
+	targets := f.targets
+	timeout := f.WriteTimeout
+	errs := make([]error, len(targets))
+	writing := make([]bool, len(targets))
+	for index, target := range targets {
+		if target.stuck {
+			target.failed(errors.New("still writing an earlier packet"))
+			errs[index] = fmt.Errorf("%s: %w", target.stats.Name, target.stats.LastError)
+			continue
+		}
+		writing[index] = true
+	}
+	f.mutex.Unlock()
+
+	done := make(chan struct{}, len(targets))
+	pending := 0
+	for index, target := range targets {
+		if !writing[index] {
+			continue
+		}
+		pending++
+		go func() {
+			start := time.Now()
+			err := target.conn.WritePacket(encoded)
+			latency := time.Since(start)
+
+			f.mutex.Lock()
+			defer f.mutex.Unlock()
+			target.stats.TotalLatency += latency
+			target.stats.MaxLatency = max(target.stats.MaxLatency, latency)
+			writing[index] = false
+			if target.stuck {
+				// NOTE: this write was already counted as failed when it timed out
+				target.stuck = false
+			} else if err != nil {
+				target.failed(err)
+				errs[index] = fmt.Errorf("%s: %w", target.stats.Name, err)
+			} else {
+				target.stats.Sent++
+			}
+			done <- struct{}{}
+		}()
+	}
+
+	var timedOut <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timedOut = timer.C
+	}
+wait:
+	for ; pending > 0; pending-- {
+		select {
+		case <-done:
+		case <-timedOut:
+			f.mutex.Lock()
+			for index, target := range targets {
+				if writing[index] {
+					target.stuck = true
+					target.failed(fmt.Errorf("write timed out after %s", timeout))
+					errs[index] = fmt.Errorf("%s: %w", target.stats.Name, target.stats.LastError)
+				}
+			}
+			f.mutex.Unlock()
+			break wait
+		}
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return errors.Join(errs...)
+}
+
+func (t *fanOutTarget) failed(err error) {
+	t.stats.Failed++
+	t.stats.LastError = err
+}
+
+// Stats returns a snapshot of every target's stats in the order they were added
+func (f *FanOut) Stats() []TargetStats {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	stats := []TargetStats{}
+	for _, target := range f.targets {
+		stats = append(stats, target.stats)
+	}
+	return stats
+}
+
+// Close closes every target Conn
+func (f *FanOut) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	errs := []error{}
+	for _, target := range f.targets {
+		errs = append(errs, target.conn.Close())
+	}
+	return errors.Join(errs...)
+}
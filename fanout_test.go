@@ -0,0 +1,235 @@
+package osc
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countingPacket struct {
+	packet  OSCPacket
+	encodes int
+}
+
+func (p *countingPacket) ToBytes() ([]byte, error) {
+	p.encodes++
+	return p.packet.ToBytes()
+}
+
+func TestFanOutDelivers(t *testing.T) {
+	fanOut := NewFanOut()
+	defer fanOut.Close()
+
+	readers := []Conn{}
+	for index := 0; index < 3; index++ {
+		dest, err := ListenUDP("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %s", err.Error())
+		}
+		defer dest.Close()
+		dest.SetReadDeadline(time.Now().Add(time.Second))
+
+		conn, err := DialUDP("udp", dest.LocalAddr().String())
+		if err != nil {
+			t.Fatalf("failed to dial: %s", err.Error())
+		}
+		fanOut.Add(dest.LocalAddr().String(), conn)
+		readers = append(readers, dest)
+	}
+
+	listener, err := ListenStream("tcp", "127.0.0.1:0", SLIPFraming)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer listener.Close()
+	conn, err := DialStream("tcp", listener.Addr().String(), SLIPFraming)
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	fanOut.Add(listener.Addr().String(), conn)
+	accepted, err := listener.AcceptStream()
+	if err != nil {
+		t.Fatalf("failed to accept: %s", err.Error())
+	}
+	defer accepted.Close()
+	readers = append(readers, accepted)
+
+	message := &OSCMessage{Address: "/mirror", Args: []OSCArg{{Type: "f", Value: float32(0.25)}}}
+	packet := &countingPacket{packet: message}
+	err = fanOut.WritePacket(packet)
+	if err != nil {
+		t.Fatalf("failed to write packet: %s", err.Error())
+	}
+	if packet.encodes != 1 {
+		t.Fatalf("failed to encode once got %d encodes, expected 1", packet.encodes)
+	}
+
+	for _, reader := range readers {
+		got, _, err := reader.ReadPacket()
+		if err != nil {
+			t.Fatalf("failed to read packet: %s", err.Error())
+		}
+		if !reflect.DeepEqual(got, message) {
+			t.Fatalf("failed to deliver packet got '%v', expected '%v'", got, message)
+		}
+	}
+
+	for _, stats := range fanOut.Stats() {
+		if stats.Sent != 1 || stats.Failed != 0 {
+			t.Fatalf("failed to count writes to %s got %d sent %d failed, expected 1 sent 0 failed", stats.Name, stats.Sent, stats.Failed)
+		}
+	}
+}
+
+func TestFanOutTargetFailure(t *testing.T) {
+	fanOut := NewFanOut()
+	defer fanOut.Close()
+
+	dest, err := ListenUDP("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer dest.Close()
+	dest.SetReadDeadline(time.Now().Add(time.Second))
+
+	good, err := DialUDP("udp", dest.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	bad, err := DialUDP("udp", dest.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	bad.Close()
+
+	fanOut.Add("bad", bad)
+	fanOut.Add("good", good)
+
+	message := &OSCMessage{Address: "/mirror", Args: []OSCArg{}}
+	err = fanOut.WritePacket(message)
+	if err == nil {
+		t.Fatalf("expected write to the closed target to fail")
+	}
+	if !strings.HasPrefix(err.Error(), "bad: ") {
+		t.Fatalf("failed to name the failed target got '%s'", err.Error())
+	}
+
+	got, _, err := dest.ReadPacket()
+	if err != nil {
+		t.Fatalf("failed to read packet from the good target: %s", err.Error())
+	}
+	if !reflect.DeepEqual(got, message) {
+		t.Fatalf("failed to deliver packet got '%v', expected '%v'", got, message)
+	}
+
+	stats := fanOut.Stats()
+	if stats[0].Failed != 1 || stats[0].LastError == nil {
+		t.Fatalf("failed to count the failure got %d failed, expected 1", stats[0].Failed)
+	}
+	if stats[1].Sent != 1 {
+		t.Fatalf("failed to count the write got %d sent, expected 1", stats[1].Sent)
+	}
+}
+
+func TestFanOutMaxPacketSize(t *testing.T) {
+	listener, err := ListenUDP("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer listener.Close()
+
+	client, err := UDPConfig{MaxPacketSize: 48}.Dial("udp4", listener.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	fanOut := NewFanOut()
+	fanOut.Add("udp", client)
+	defer fanOut.Close()
+
+	timeTag := ImmediateTimeTag()
+	a := &OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: int32(1)}}}
+	b := &OSCMessage{Address: "/b", Args: []OSCArg{{Type: "i", Value: int32(2)}}}
+	c := &OSCMessage{Address: "/c", Args: []OSCArg{{Type: "i", Value: int32(3)}}}
+
+	err = fanOut.WritePacket(&OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a, b, c}})
+	if err != nil {
+		t.Fatalf("failed to write bundle: %s", err.Error())
+	}
+
+	expected := []OSCPacket{
+		&OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a, b}},
+		&OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{c}},
+	}
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	for _, want := range expected {
+		got, _, err := listener.ReadPacket()
+		if err != nil {
+			t.Fatalf("failed to read packet: %s", err.Error())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("failed to split bundle got '%v', expected '%v'", got, want)
+		}
+	}
+}
+
+type stalledConn struct {
+	release chan struct{}
+}
+
+func (c *stalledConn) ReadPacket() (OSCPacket, net.Addr, error) {
+	return nil, nil, errors.New("not readable")
+}
+
+func (c *stalledConn) WritePacket(packet OSCPacket) error {
+	<-c.release
+	return nil
+}
+
+func (c *stalledConn) Close() error {
+	return nil
+}
+
+func TestFanOutStalledTarget(t *testing.T) {
+	fanOut := NewFanOut()
+	fanOut.WriteTimeout = 50 * time.Millisecond
+
+	stalled := &stalledConn{release: make(chan struct{})}
+	healthy := newRecordingWriter()
+	fanOut.Add("stalled", stalled)
+	fanOut.Add("healthy", packetWriterConn{healthy})
+
+	message := &OSCMessage{Address: "/mirror", Args: []OSCArg{}}
+	for index := 0; index < 3; index++ {
+		err := fanOut.WritePacket(message)
+		if err == nil || !strings.HasPrefix(err.Error(), "stalled: ") {
+			t.Fatalf("failed to report the stalled target on write %d got '%v'", index, err)
+		}
+	}
+	close(stalled.release)
+
+	if len(healthy.Packets()) != 3 {
+		t.Fatalf("failed to deliver to the healthy target got %d packets, expected 3", len(healthy.Packets()))
+	}
+	stats := fanOut.Stats()
+	if stats[0].Failed != 3 || stats[0].Sent != 0 {
+		t.Fatalf("failed to count the stalled target got %d sent %d failed, expected 0 sent 3 failed", stats[0].Sent, stats[0].Failed)
+	}
+	if stats[1].Sent != 3 {
+		t.Fatalf("failed to count the healthy target got %d sent, expected 3", stats[1].Sent)
+	}
+}
+
+type packetWriterConn struct {
+	PacketWriter
+}
+
+func (c packetWriterConn) ReadPacket() (OSCPacket, net.Addr, error) {
+	return nil, nil, errors.New("not readable")
+}
+
+func (c packetWriterConn) Close() error {
+	return nil
+}
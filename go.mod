@@ -2,4 +2,9 @@ module github.com/jwetzell/osc-go
 
 go 1.25.1
 
-require github.com/urfave/cli/v3 v3.10.1
+require (
+	github.com/urfave/cli/v3 v3.10.1
+	golang.org/x/net v0.58.0
+	golang.org/x/sys v0.47.0
+	golang.org/x/term v0.45.0
+)
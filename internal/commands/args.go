@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+func argToTypedArg(rawArg string, oscType string) (osc.OSCArg, error) {
+
+	switch oscType {
+	case "s":
+		return osc.OSCArg{
+			Value: rawArg,
+			Type:  "s",
+		}, nil
+	case "i":
+		number, err := strconv.ParseInt(rawArg, 10, 32)
+		if err != nil {
+			return osc.OSCArg{}, err
+		}
+		return osc.OSCArg{
+			Value: int32(number),
+			Type:  "i",
+		}, nil
+	case "f":
+		number, err := strconv.ParseFloat(rawArg, 32)
+		if err != nil {
+			return osc.OSCArg{}, err
+		}
+		return osc.OSCArg{
+			Value: float32(number),
+			Type:  "f",
+		}, nil
+	case "b":
+		data, err := hex.DecodeString(rawArg)
+		if err != nil {
+			return osc.OSCArg{}, err
+		}
+		return osc.OSCArg{
+			Value: data,
+			Type:  "b",
+		}, nil
+	case "h":
+		number, err := strconv.ParseInt(rawArg, 10, 64)
+		if err != nil {
+			return osc.OSCArg{}, err
+		}
+		return osc.OSCArg{
+			Value: int64(number),
+			Type:  "h",
+		}, nil
+	case "d":
+		number, err := strconv.ParseFloat(rawArg, 64)
+		if err != nil {
+			return osc.OSCArg{}, err
+		}
+		return osc.OSCArg{
+			Value: float64(number),
+			Type:  "d",
+		}, nil
+	case "T":
+		return osc.OSCArg{
+			Value: true,
+			Type:  "T",
+		}, nil
+	case "F":
+		return osc.OSCArg{
+			Value: false,
+			Type:  "F",
+		}, nil
+	case "N":
+		return osc.OSCArg{
+			Value: nil,
+			Type:  "N",
+		}, nil
+	default:
+		return osc.OSCArg{}, fmt.Errorf("unsupported OSC arg type: %s", oscType)
+	}
+}
+
+// messageFromFlags builds a message from --address, --arg and --type, args without a type are strings
+func messageFromFlags(address string, args []string, types []string) (*osc.OSCMessage, error) {
+	message := &osc.OSCMessage{
+		Address: address,
+		Args:    []osc.OSCArg{},
+	}
+
+	for index, rawArg := range args {
+		oscType := "s"
+		if len(types) > index {
+			oscType = types[index]
+		}
+		arg, err := argToTypedArg(rawArg, oscType)
+		if err != nil {
+			return nil, err
+		}
+		message.Args = append(message.Args, arg)
+	}
+	return message, nil
+}
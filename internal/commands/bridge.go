@@ -0,0 +1,266 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+	"github.com/urfave/cli/v3"
+)
+
+var bridgeProtocols = []string{"udp", "udp4", "udp6", "tcp", "tcp4", "tcp6", "unix", "unixgram", "ws", "serial"}
+
+// endpoint is one side of a bridge written as protocol://address?options
+type endpoint struct {
+	protocol   string
+	address    string
+	slip       bool
+	jsonFrames bool
+	baud       int
+	filters    []string
+	coalesce   time.Duration
+}
+
+// parseEndpoint reads specs like udp://:8000, tcp://10.0.0.5:3032?slip, serial:///dev/ttyUSB0?baud=9600 or ws://host:8080/path?json&coalesce=20ms
+func parseEndpoint(spec string) (endpoint, error) {
+	specURL, err := url.Parse(spec)
+	if err != nil {
+		return endpoint{}, err
+	}
+	if !slices.Contains(bridgeProtocols, specURL.Scheme) {
+		return endpoint{}, fmt.Errorf("endpoint %s protocol must be one of %s", spec, strings.Join(bridgeProtocols, ", "))
+	}
+
+	query := specURL.Query()
+	e := endpoint{
+		protocol:   specURL.Scheme,
+		slip:       query.Has("slip"),
+		jsonFrames: query.Has("json"),
+		baud:       115200,
+		filters:    query["filter"],
+	}
+	if query.Has("coalesce") {
+		e.coalesce, err = time.ParseDuration(query.Get("coalesce"))
+		if err != nil || e.coalesce <= 0 {
+			return endpoint{}, fmt.Errorf("endpoint %s has an invalid coalesce interval: %s", spec, query.Get("coalesce"))
+		}
+	}
+	if query.Has("baud") {
+		e.baud, err = strconv.Atoi(query.Get("baud"))
+		if err != nil {
+			return endpoint{}, fmt.Errorf("endpoint %s has an invalid baud rate: %s", spec, query.Get("baud"))
+		}
+	}
+
+	switch e.protocol {
+	case "unix", "unixgram", "serial":
+		e.address = specURL.Path
+	case "ws":
+		e.address = "ws://" + specURL.Host + specURL.Path
+	default:
+		e.address = specURL.Host
+	}
+	if e.address == "" || e.address == "ws://" {
+		return endpoint{}, fmt.Errorf("endpoint %s is missing an address", spec)
+	}
+	return e, nil
+}
+
+func (e endpoint) framing() osc.Framing {
+	if e.slip {
+		return osc.SLIPFraming
+	}
+	return osc.SizeFraming
+}
+
+func (e endpoint) dial(udpConfig osc.UDPConfig) (osc.Conn, error) {
+	return dial(e.address, e.protocol, e.slip, udpConfig, nil, e.jsonFrames, e.baud)
+}
+
+func (e endpoint) serve(server *osc.Server) error {
+	server.Framing = e.framing()
+	switch e.protocol {
+	case "ws":
+		listenURL, err := url.Parse(e.address)
+		if err != nil {
+			return err
+		}
+		return server.ListenAndServe(e.protocol, listenURL.Host)
+	case "serial":
+		conn, err := osc.OpenSerial(e.address, e.baud)
+		if err != nil {
+			return err
+		}
+		return server.Serve(conn)
+	default:
+		return server.ListenAndServe(e.protocol, e.address)
+	}
+}
+
+func Bridge() *cli.Command {
+	return &cli.Command{
+		Name:  "bridge",
+		Usage: "receive OSC on one transport and forward it to destinations on others",
+		Description: "endpoints are written as protocol://address with options as query parameters, " +
+			"?slip for SLIP framing on tcp, unix and serial, ?baud=N for serial, ?json for ws, ?filter=PATTERN (repeatable) to only forward matching messages to a destination " +
+			"and ?coalesce=DURATION to send a destination only the latest message for each address at most once per DURATION\n\n" +
+			"  osc bridge --from udp://:8000 --to 'tcp://10.0.0.5:3032?slip' --to 'serial:///dev/ttyUSB0?filter=/eos/*'",
+		// NOTE: address patterns use commas in {} string lists
+		DisableSliceFlagSeparator: true,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "from",
+				Usage:    fmt.Sprintf("endpoint to receive OSC on (%s)", strings.Join(bridgeProtocols, ", ")),
+				Required: true,
+			},
+			&cli.StringSliceFlag{
+				Name:     "to",
+				Usage:    "endpoint to forward OSC to (repeatable)",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "bidirectional",
+				Value: false,
+				Usage: "whether to forward replies from destinations back to the sender",
+			},
+			&cli.StringFlag{
+				Name:  "rules",
+				Usage: "JSON rules file to rewrite addresses and arguments with before forwarding",
+			},
+			&cli.DurationFlag{
+				Name:  "loop-window",
+				Value: 100 * time.Millisecond,
+				Usage: "drop packets identical to one forwarded within this long that come back from somewhere else, 0 disables it",
+			},
+			&cli.BoolFlag{
+				Name:  "verbose",
+				Value: false,
+				Usage: "whether to print every packet forwarded",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			from, err := parseEndpoint(cmd.String("from"))
+			if err != nil {
+				return err
+			}
+			if len(from.filters) > 0 {
+				return fmt.Errorf("filter is only supported on --to endpoints")
+			}
+
+			relay := osc.NewRelay()
+			relay.LoopWindow = cmd.Duration("loop-window")
+			if cmd.IsSet("rules") {
+				rulesJSON, err := os.ReadFile(cmd.String("rules"))
+				if err != nil {
+					return err
+				}
+				relay.Rules, err = osc.RuleSetFromJSON(rulesJSON)
+				if err != nil {
+					return fmt.Errorf("%s: %w", cmd.String("rules"), err)
+				}
+			}
+			relay.ErrorHandler = func(err error, addr net.Addr) {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", addr, err)
+			}
+			defer relay.Close()
+
+			destinations := []osc.Conn{}
+			for _, spec := range cmd.StringSlice("to") {
+				to, err := parseEndpoint(spec)
+				if err != nil {
+					return err
+				}
+				conn, err := to.dial(osc.UDPConfig{})
+				if err != nil {
+					return err
+				}
+				if to.coalesce > 0 {
+					conn = coalesce(ctx, conn, to.coalesce)
+				}
+				if err := relay.AddRoute(conn, to.filters...); err != nil {
+					conn.Close()
+					return err
+				}
+				destinations = append(destinations, conn)
+			}
+
+			if cmd.Bool("bidirectional") {
+				for _, conn := range destinations {
+					go func() {
+						if err := relay.ServeReplies(conn); err != nil {
+							fmt.Fprintln(os.Stderr, err)
+						}
+					}()
+				}
+			}
+
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				<-signals
+				relay.Close()
+				if from.protocol == "unix" || from.protocol == "unixgram" {
+					os.Remove(from.address)
+				}
+				os.Exit(0)
+			}()
+
+			var handler osc.Handler = relay
+			if cmd.Bool("verbose") {
+				handler = osc.HandlerFunc(func(w osc.PacketWriter, packet osc.OSCPacket, source net.Addr) {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", source, packet)
+					relay.ServeOSC(w, packet, source)
+				})
+			}
+			return from.serve(&osc.Server{
+				Handler: handler,
+				ErrorHandler: func(err error, source net.Addr) {
+					fmt.Fprintf(os.Stderr, "%s: %s\n", source, err)
+				},
+			})
+		},
+	}
+}
+
+// coalescedConn only writes the latest message for each address to a destination that can't keep up
+type coalescedConn struct {
+	osc.Conn
+	coalescer *osc.Coalescer
+}
+
+func coalesce(ctx context.Context, conn osc.Conn, interval time.Duration) *coalescedConn {
+	coalesced := &coalescedConn{
+		Conn:      conn,
+		coalescer: osc.NewCoalescer(),
+	}
+	coalesced.coalescer.ErrorHandler = func(err error) {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	go coalesced.coalescer.Drain(ctx, conn, interval)
+	return coalesced
+}
+
+func (c *coalescedConn) WritePacket(packet osc.OSCPacket) error {
+	return c.coalescer.WritePacket(packet)
+}
+
+func (c *coalescedConn) RemoteAddr() net.Addr {
+	if connected, ok := c.Conn.(interface{ RemoteAddr() net.Addr }); ok {
+		return connected.RemoteAddr()
+	}
+	return nil
+}
+
+func (c *coalescedConn) Close() error {
+	c.coalescer.Close()
+	return c.Conn.Close()
+}
@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseEndpoint(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		spec     string
+		expected endpoint
+	}{
+		{
+			name:     "udp",
+			spec:     "udp://:8000",
+			expected: endpoint{protocol: "udp", address: ":8000", baud: 115200},
+		},
+		{
+			name:     "tcp with slip",
+			spec:     "tcp://10.0.0.5:3032?slip",
+			expected: endpoint{protocol: "tcp", address: "10.0.0.5:3032", slip: true, baud: 115200},
+		},
+		{
+			name:     "serial with baud and filters",
+			spec:     "serial:///dev/ttyUSB0?baud=9600&filter=/eos/*&filter=/mixer/*",
+			expected: endpoint{protocol: "serial", address: "/dev/ttyUSB0", baud: 9600, filters: []string{"/eos/*", "/mixer/*"}},
+		},
+		{
+			name:     "websocket with json and coalesce",
+			spec:     "ws://host:8080/osc?json&coalesce=20ms",
+			expected: endpoint{protocol: "ws", address: "ws://host:8080/osc", jsonFrames: true, baud: 115200, coalesce: 20 * time.Millisecond},
+		},
+		{
+			name:     "unix socket",
+			spec:     "unixgram:///tmp/osc.sock",
+			expected: endpoint{protocol: "unixgram", address: "/tmp/osc.sock", baud: 115200},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := parseEndpoint(testCase.spec)
+			if err != nil {
+				t.Fatalf("failed to parse endpoint: %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to parse endpoint got '%+v', expected '%+v'", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestBadParseEndpoint(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		spec     string
+		errorMsg string
+	}{
+		{
+			name:     "unknown protocol",
+			spec:     "http://host:80",
+			errorMsg: "endpoint http://host:80 protocol must be one of udp, udp4, udp6, tcp, tcp4, tcp6, unix, unixgram, ws, serial",
+		},
+		{
+			name:     "missing address",
+			spec:     "udp://",
+			errorMsg: "endpoint udp:// is missing an address",
+		},
+		{
+			name:     "bad baud rate",
+			spec:     "serial:///dev/ttyUSB0?baud=fast",
+			errorMsg: "endpoint serial:///dev/ttyUSB0?baud=fast has an invalid baud rate: fast",
+		},
+		{
+			name:     "bad coalesce interval",
+			spec:     "udp://:8000?coalesce=-1s",
+			errorMsg: "endpoint udp://:8000?coalesce=-1s has an invalid coalesce interval: -1s",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := parseEndpoint(testCase.spec)
+			if err == nil {
+				t.Fatalf("expected endpoint to fail")
+			}
+			if err.Error() != testCase.errorMsg {
+				t.Fatalf("failed to reject endpoint got '%s', expected '%s'", err.Error(), testCase.errorMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Package commands holds the osc command line tools, each one is available as a subcommand of osc and as its own binary
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+)
+
+// Root is the osc command with every tool as a subcommand
+func Root() *cli.Command {
+	return &cli.Command{
+		Name:  "osc",
+		Usage: "send, receive, make, parse and bridge OSC packets or explore a device in a shell",
+		Commands: []*cli.Command{
+			Send(),
+			Make(),
+			Receive(),
+			Parse(),
+			Shell(),
+			Bridge(),
+		},
+	}
+}
+
+// Run runs cmd with the process arguments, exiting with status 1 on error
+func Run(cmd *cli.Command) {
+	if err := cmd.Run(context.Background(), os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
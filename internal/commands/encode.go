@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var encodings = []string{"raw", "hex", "hexdump", "base64", "c", "go", "escaped", "size"}
+
+// encodeBytes renders packet bytes for --output, every encoding but raw and size is text ending in a newline
+func encodeBytes(data []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case "raw":
+		return data, nil
+	case "hex":
+		return []byte(hex.EncodeToString(data) + "\n"), nil
+	case "hexdump":
+		return []byte(hex.Dump(data)), nil
+	case "base64":
+		return []byte(base64.StdEncoding.EncodeToString(data) + "\n"), nil
+	case "c":
+		return []byte(fmt.Sprintf("uint8_t packet[%d] = {%s};\n", len(data), byteList(data))), nil
+	case "go":
+		return []byte(fmt.Sprintf("[]byte{%s}\n", byteList(data))), nil
+	case "escaped":
+		return []byte(strconv.Quote(string(data)) + "\n"), nil
+	case "size":
+		return append(binary.BigEndian.AppendUint32(nil, uint32(len(data))), data...), nil
+	default:
+		return nil, fmt.Errorf("unsupported output encoding: %s", encoding)
+	}
+}
+
+// byteList formats bytes as 0x.. literals, 12 to a line
+func byteList(data []byte) string {
+	var sb strings.Builder
+	for index, b := range data {
+		if index%12 == 0 {
+			sb.WriteString("\n\t")
+		} else {
+			sb.WriteString(" ")
+		}
+		fmt.Fprintf(&sb, "0x%02x,", b)
+	}
+	if len(data) > 0 {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
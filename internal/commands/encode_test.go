@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEncodeBytes(t *testing.T) {
+	data := []byte{0x2f, 0x61, 0x00, 0x00}
+
+	testCases := []struct {
+		name     string
+		encoding string
+		expected []byte
+	}{
+		{name: "raw", encoding: "raw", expected: data},
+		{name: "hex", encoding: "hex", expected: []byte("2f610000\n")},
+		{name: "base64", encoding: "base64", expected: []byte("L2EAAA==\n")},
+		{name: "c", encoding: "c", expected: []byte("uint8_t packet[4] = {\n\t0x2f, 0x61, 0x00, 0x00,\n};\n")},
+		{name: "go", encoding: "go", expected: []byte("[]byte{\n\t0x2f, 0x61, 0x00, 0x00,\n}\n")},
+		{name: "escaped", encoding: "escaped", expected: []byte("\"/a\\x00\\x00\"\n")},
+		{name: "size", encoding: "size", expected: []byte{0, 0, 0, 4, 0x2f, 0x61, 0x00, 0x00}},
+		{name: "hexdump", encoding: "hexdump", expected: []byte("00000000  2f 61 00 00                                       |/a..|\n")},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := encodeBytes(data, testCase.encoding)
+			if err != nil {
+				t.Fatalf("failed to encode: %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to encode got '%q', expected '%q'", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestBadEncodeBytes(t *testing.T) {
+	_, err := encodeBytes([]byte{}, "morse")
+	if err == nil {
+		t.Fatalf("expected unknown encoding to fail")
+	}
+	if err.Error() != "unsupported output encoding: morse" {
+		t.Fatalf("failed to reject encoding got '%s', expected '%s'", err.Error(), "unsupported output encoding: morse")
+	}
+}
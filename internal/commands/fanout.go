@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+// dialTargets opens every endpoint given with --to, see parseEndpoint for the syntax, UDP targets use udpConfig
+func dialTargets(specs []string, udpConfig osc.UDPConfig) (*osc.FanOut, error) {
+	targets := osc.NewFanOut()
+	for _, spec := range specs {
+		to, err := parseEndpoint(spec)
+		if err != nil {
+			targets.Close()
+			return nil, err
+		}
+		if len(to.filters) > 0 {
+			targets.Close()
+			return nil, fmt.Errorf("filter is not supported on send targets")
+		}
+		conn, err := to.dial(udpConfig)
+		if err != nil {
+			targets.Close()
+			return nil, err
+		}
+		targets.Add(spec, conn)
+	}
+	return targets, nil
+}
+
+// tolerantWriter reports targets that fail instead of stopping so the rest keep getting packets
+type tolerantWriter struct {
+	targets *osc.FanOut
+	errOut  io.Writer
+}
+
+func (w tolerantWriter) WritePacket(packet osc.OSCPacket) error {
+	if err := w.targets.WritePacket(packet); err != nil {
+		fmt.Fprintln(w.errOut, err)
+	}
+	return nil
+}
+
+// summarizeTargets prints each target's stats and returns an error if any writes failed
+func summarizeTargets(w io.Writer, stats []osc.TargetStats) error {
+	failedTargets := 0
+	for _, target := range stats {
+		fmt.Fprintf(w, "%s: sent %d, failed %d, latency avg %s max %s\n", target.Name, target.Sent, target.Failed, target.AverageLatency(), target.MaxLatency)
+		if target.Failed > 0 {
+			failedTargets++
+		}
+	}
+	if failedTargets > 0 {
+		return fmt.Errorf("%d of %d targets had failed writes", failedTargets, len(stats))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+func TestSummarizeTargets(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		stats    []osc.TargetStats
+		expected string
+		errorMsg string
+	}{
+		{
+			name: "all sent",
+			stats: []osc.TargetStats{
+				{Name: "udp://a:1", Sent: 2, TotalLatency: 4 * time.Millisecond, MaxLatency: 3 * time.Millisecond},
+			},
+			expected: "udp://a:1: sent 2, failed 0, latency avg 2ms max 3ms\n",
+		},
+		{
+			name: "one target failed",
+			stats: []osc.TargetStats{
+				{Name: "udp://a:1", Sent: 1, TotalLatency: time.Millisecond, MaxLatency: time.Millisecond},
+				{Name: "tcp://b:2", Failed: 1, LastError: errors.New("refused"), TotalLatency: time.Millisecond, MaxLatency: time.Millisecond},
+			},
+			expected: "udp://a:1: sent 1, failed 0, latency avg 1ms max 1ms\ntcp://b:2: sent 0, failed 1, latency avg 1ms max 1ms\n",
+			errorMsg: "1 of 2 targets had failed writes",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := summarizeTargets(&out, testCase.stats)
+			if out.String() != testCase.expected {
+				t.Fatalf("failed to summarize got '%s', expected '%s'", out.String(), testCase.expected)
+			}
+			errorMsg := ""
+			if err != nil {
+				errorMsg = err.Error()
+			}
+			if errorMsg != testCase.errorMsg {
+				t.Fatalf("failed to report failed targets got '%s', expected '%s'", errorMsg, testCase.errorMsg)
+			}
+		})
+	}
+}
+
+func TestDialTargets(t *testing.T) {
+	listener, err := osc.ListenUDP("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer listener.Close()
+
+	spec := "udp://" + listener.LocalAddr().String()
+	targets, err := dialTargets([]string{spec}, osc.UDPConfig{})
+	if err != nil {
+		t.Fatalf("failed to dial targets: %s", err.Error())
+	}
+	defer targets.Close()
+
+	stats := targets.Stats()
+	if len(stats) != 1 || stats[0].Name != spec {
+		t.Fatalf("failed to add target got '%+v'", stats)
+	}
+}
+
+func TestBadDialTargets(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		specs    []string
+		errorMsg string
+	}{
+		{
+			name:     "filter",
+			specs:    []string{"udp://127.0.0.1:9000?filter=/a"},
+			errorMsg: "filter is not supported on send targets",
+		},
+		{
+			name:     "bad endpoint",
+			specs:    []string{"udp://127.0.0.1:9000", "udp://"},
+			errorMsg: "endpoint udp:// is missing an address",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := dialTargets(testCase.specs, osc.UDPConfig{})
+			if err == nil {
+				t.Fatalf("expected targets to fail")
+			}
+			if err.Error() != testCase.errorMsg {
+				t.Fatalf("failed to reject targets got '%s', expected '%s'", err.Error(), testCase.errorMsg)
+			}
+		})
+	}
+}
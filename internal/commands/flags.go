@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	osc "github.com/jwetzell/osc-go"
+	"github.com/jwetzell/osc-go/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+// NOTE: flags hold their parsed value so every command needs its own instances
+
+func protocolFlag(protocols []string, usage string) cli.Flag {
+	return &cli.StringFlag{
+		Name:  "protocol",
+		Usage: fmt.Sprintf(usage, strings.Join(protocols, ", ")),
+		Value: "udp",
+		Validator: func(flag string) error {
+			if !slices.Contains(protocols, flag) {
+				return fmt.Errorf("protocol must be one of %s", strings.Join(protocols, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+func formatFlag(usage string) cli.Flag {
+	return &cli.StringFlag{
+		Name:  "format",
+		Usage: fmt.Sprintf(usage, strings.Join(output.Formats, ", ")),
+		Value: "json",
+		Validator: func(flag string) error {
+			if !slices.Contains(output.Formats, flag) {
+				return fmt.Errorf("format must be one of %s", strings.Join(output.Formats, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+func ipFamilyFlags(verb string) []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "ipv4",
+			Value: false,
+			Usage: fmt.Sprintf("whether to only %s over IPv4", verb),
+		},
+		&cli.BoolFlag{
+			Name:  "ipv6",
+			Value: false,
+			Usage: fmt.Sprintf("whether to only %s over IPv6", verb),
+		},
+	}
+}
+
+func localTransportFlags(verb string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "socket",
+			Usage: fmt.Sprintf("unix domain socket path to %s OSC messages (unix and unixgram protocols)", verb),
+		},
+		&cli.StringFlag{
+			Name:  "device",
+			Usage: fmt.Sprintf("serial device to %s SLIP encoded OSC messages (serial protocol)", verb),
+		},
+		&cli.IntFlag{
+			Name:  "baud",
+			Usage: "baud rate of the serial device (serial protocol)",
+			Value: 115200,
+		},
+	}
+}
+
+func slipFlag(usage string) cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "slip",
+		Value: false,
+		Usage: usage,
+	}
+}
+
+// hmacKeyFlag also reads OSC_HMAC_KEY so the key can be kept out of the process list
+func hmacKeyFlag(usage string) cli.Flag {
+	return &cli.StringFlag{
+		Name:    "hmac-key",
+		Usage:   usage,
+		Sources: cli.EnvVars("OSC_HMAC_KEY"),
+	}
+}
+
+func tlsFlags(certUsage string, caUsage string) []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "tls",
+			Value: false,
+			Usage: "whether to use TLS (tcp protocol)",
+		},
+		&cli.StringFlag{
+			Name:  "cert",
+			Usage: certUsage,
+		},
+		&cli.StringFlag{
+			Name:  "key",
+			Usage: "PEM private key file of --cert (--tls)",
+		},
+		&cli.StringFlag{
+			Name:  "ca",
+			Usage: caUsage,
+		},
+	}
+}
+
+func messageFlags(argUsage string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "address",
+			Usage: "OSC address",
+		},
+		&cli.StringSliceFlag{
+			Name:  "arg",
+			Usage: argUsage,
+			Value: []string{},
+		},
+		&cli.StringSliceFlag{
+			Name:  "type",
+			Usage: "OSC types",
+			Value: []string{},
+		},
+	}
+}
+
+func streamFraming(cmd *cli.Command) osc.Framing {
+	if cmd.Bool("slip") {
+		return osc.SLIPFraming
+	}
+	return osc.SizeFraming
+}
+
+// network picks the Go network name, a bare udp or tcp listening on :: is dual-stack
+func network(protocol string, ipv4 bool, ipv6 bool) string {
+	if ipv4 {
+		return protocol + "4"
+	}
+	if ipv6 {
+		return protocol + "6"
+	}
+	return protocol
+}
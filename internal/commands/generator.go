@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+// generator produces the value of an arg for the packet sent at offset from the start
+type generator func(offset time.Duration, index int) float64
+
+var generatorUsage = map[string]string{
+	"ramp":     "ramp:FROM:TO:DURATION",
+	"sine":     "sine:MIN:MAX:PERIOD",
+	"triangle": "triangle:MIN:MAX:PERIOD",
+	"random":   "random:MIN:MAX",
+	"step":     "step:VALUE:VALUE...",
+}
+
+// timedGenerator reports whether spec is a generator that follows the time since the first packet, like ramp
+func timedGenerator(spec string) bool {
+	name, _, _ := strings.Cut(spec, ":")
+	return name == "ramp" || name == "sine" || name == "triangle"
+}
+
+// parseGenerator parses a generator spec like ramp:0:1:2s, ok is false if spec is not a generator
+func parseGenerator(spec string) (generator, bool, error) {
+	name, params, found := strings.Cut(spec, ":")
+	usage, known := generatorUsage[name]
+	if !found || !known {
+		return nil, false, nil
+	}
+	parts := strings.Split(params, ":")
+
+	numbers := func(count int) ([]float64, error) {
+		if len(parts) < count {
+			return nil, fmt.Errorf("%s generator expects %s", name, usage)
+		}
+		values := []float64{}
+		for _, part := range parts[:count] {
+			value, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s generator expects %s but %s is not a number", name, usage, part)
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	period := func() (time.Duration, error) {
+		if len(parts) != 3 {
+			return 0, fmt.Errorf("%s generator expects %s", name, usage)
+		}
+		duration, err := time.ParseDuration(parts[2])
+		if err != nil || duration <= 0 {
+			return 0, fmt.Errorf("%s generator expects %s but %s is not a positive duration", name, usage, parts[2])
+		}
+		return duration, nil
+	}
+
+	switch name {
+	case "ramp":
+		values, err := numbers(2)
+		if err != nil {
+			return nil, true, err
+		}
+		duration, err := period()
+		if err != nil {
+			return nil, true, err
+		}
+		from, to := values[0], values[1]
+		return func(offset time.Duration, index int) float64 {
+			// NOTE: the ramp holds at TO once the duration has passed
+			progress := min(float64(offset)/float64(duration), 1)
+			return from + (to-from)*progress
+		}, true, nil
+	case "sine", "triangle":
+		values, err := numbers(2)
+		if err != nil {
+			return nil, true, err
+		}
+		duration, err := period()
+		if err != nil {
+			return nil, true, err
+		}
+		low, high := values[0], values[1]
+		if name == "sine" {
+			return func(offset time.Duration, index int) float64 {
+				phase := float64(offset) / float64(duration)
+				return low + (high-low)*(1+math.Sin(2*math.Pi*phase))/2
+			}, true, nil
+		}
+		return func(offset time.Duration, index int) float64 {
+			phase := math.Mod(float64(offset)/float64(duration), 1)
+			if phase < 0.5 {
+				return low + (high-low)*2*phase
+			}
+			return low + (high-low)*(2-2*phase)
+		}, true, nil
+	case "random":
+		if len(parts) != 2 {
+			return nil, true, fmt.Errorf("%s generator expects %s", name, usage)
+		}
+		values, err := numbers(2)
+		if err != nil {
+			return nil, true, err
+		}
+		low, high := values[0], values[1]
+		return func(offset time.Duration, index int) float64 {
+			return low + (high-low)*rand.Float64()
+		}, true, nil
+	case "step":
+		values, err := numbers(len(parts))
+		if err != nil {
+			return nil, true, err
+		}
+		return func(offset time.Duration, index int) float64 {
+			return values[index%len(values)]
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+// generatedArg converts a generated value to oscType, rejecting values an integer type can't hold instead of wrapping them
+func generatedArg(value float64, oscType string) (osc.OSCArg, error) {
+	switch oscType {
+	case "i":
+		rounded := math.Round(value)
+		if math.IsNaN(rounded) || rounded < math.MinInt32 || rounded > math.MaxInt32 {
+			return osc.OSCArg{}, fmt.Errorf("OSC arg value %v does not fit in an int32", value)
+		}
+		return osc.OSCArg{Type: "i", Value: int32(rounded)}, nil
+	case "h":
+		rounded := math.Round(value)
+		if math.IsNaN(rounded) || rounded < math.MinInt64 || rounded >= math.MaxInt64 {
+			return osc.OSCArg{}, fmt.Errorf("OSC arg value %v does not fit in an int64", value)
+		}
+		return osc.OSCArg{Type: "h", Value: int64(rounded)}, nil
+	case "f":
+		return osc.OSCArg{Type: "f", Value: float32(value)}, nil
+	default:
+		return osc.OSCArg{Type: "d", Value: value}, nil
+	}
+}
@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+func TestParseGenerator(t *testing.T) {
+
+	type sample struct {
+		offset   time.Duration
+		index    int
+		expected float64
+	}
+
+	testCases := []struct {
+		name    string
+		spec    string
+		timed   bool
+		samples []sample
+	}{
+		{
+			name:  "ramp holds at the end",
+			spec:  "ramp:0:10:2s",
+			timed: true,
+			samples: []sample{
+				{offset: 0, expected: 0},
+				{offset: time.Second, expected: 5},
+				{offset: 3 * time.Second, expected: 10},
+			},
+		},
+		{
+			name:  "sine",
+			spec:  "sine:0:2:4s",
+			timed: true,
+			samples: []sample{
+				{offset: 0, expected: 1},
+				{offset: time.Second, expected: 2},
+				{offset: 3 * time.Second, expected: 0},
+			},
+		},
+		{
+			name:  "triangle",
+			spec:  "triangle:0:1:2s",
+			timed: true,
+			samples: []sample{
+				{offset: 0, expected: 0},
+				{offset: 500 * time.Millisecond, expected: 0.5},
+				{offset: time.Second, expected: 1},
+				{offset: 1500 * time.Millisecond, expected: 0.5},
+			},
+		},
+		{
+			name:  "step follows the packet index",
+			spec:  "step:1:2:3",
+			timed: false,
+			samples: []sample{
+				{index: 0, expected: 1},
+				{index: 1, expected: 2},
+				{index: 3, expected: 1},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			generate, ok, err := parseGenerator(testCase.spec)
+			if err != nil {
+				t.Fatalf("failed to parse generator: %s", err.Error())
+			}
+			if !ok {
+				t.Fatalf("failed to recognize generator %s", testCase.spec)
+			}
+			if timedGenerator(testCase.spec) != testCase.timed {
+				t.Fatalf("failed to tell if the generator is timed got %t, expected %t", timedGenerator(testCase.spec), testCase.timed)
+			}
+			for _, sample := range testCase.samples {
+				got := generate(sample.offset, sample.index)
+				if math.Abs(got-sample.expected) > 1e-9 {
+					t.Fatalf("failed to generate at %s index %d got %v, expected %v", sample.offset, sample.index, got, sample.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestParseGeneratorRandom(t *testing.T) {
+	generate, ok, err := parseGenerator("random:5:6")
+	if err != nil || !ok {
+		t.Fatalf("failed to parse random generator")
+	}
+	for index := 0; index < 100; index++ {
+		got := generate(0, index)
+		if got < 5 || got >= 6 {
+			t.Fatalf("failed to stay in range got %v, expected [5, 6)", got)
+		}
+	}
+}
+
+func TestBadParseGenerator(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		spec     string
+		errorMsg string
+	}{
+		{name: "missing duration", spec: "ramp:0:1", errorMsg: "ramp generator expects ramp:FROM:TO:DURATION"},
+		{name: "bad number", spec: "sine:low:1:1s", errorMsg: "sine generator expects sine:MIN:MAX:PERIOD but low is not a number"},
+		{name: "bad duration", spec: "triangle:0:1:0s", errorMsg: "triangle generator expects triangle:MIN:MAX:PERIOD but 0s is not a positive duration"},
+		{name: "extra random param", spec: "random:0:1:2", errorMsg: "random generator expects random:MIN:MAX"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, ok, err := parseGenerator(testCase.spec)
+			if !ok {
+				t.Fatalf("failed to recognize generator %s", testCase.spec)
+			}
+			if err == nil {
+				t.Fatalf("expected generator to fail")
+			}
+			if err.Error() != testCase.errorMsg {
+				t.Fatalf("failed to reject generator got '%s', expected '%s'", err.Error(), testCase.errorMsg)
+			}
+		})
+	}
+}
+
+func TestParseGeneratorNotAGenerator(t *testing.T) {
+	for _, spec := range []string{"12", "hello", "ramp", "unknown:1:2"} {
+		_, ok, err := parseGenerator(spec)
+		if ok || err != nil {
+			t.Fatalf("failed to pass %s through got ok %t err %v", spec, ok, err)
+		}
+	}
+}
+
+func TestGeneratedArg(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		value    float64
+		oscType  string
+		expected osc.OSCArg
+		errorMsg string
+	}{
+		{name: "int32 rounds", value: 2.6, oscType: "i", expected: osc.OSCArg{Type: "i", Value: int32(3)}},
+		{name: "int64", value: 5e9, oscType: "h", expected: osc.OSCArg{Type: "h", Value: int64(5e9)}},
+		{name: "float32", value: 0.5, oscType: "f", expected: osc.OSCArg{Type: "f", Value: float32(0.5)}},
+		{name: "float64", value: 0.25, oscType: "d", expected: osc.OSCArg{Type: "d", Value: float64(0.25)}},
+		{name: "int32 too big", value: 5e9, oscType: "i", errorMsg: "OSC arg value 5e+09 does not fit in an int32"},
+		{name: "int32 too small", value: -5e9, oscType: "i", errorMsg: "OSC arg value -5e+09 does not fit in an int32"},
+		{name: "int64 too big", value: 1e19, oscType: "h", errorMsg: "OSC arg value 1e+19 does not fit in an int64"},
+		{name: "NaN", value: math.NaN(), oscType: "i", errorMsg: "OSC arg value NaN does not fit in an int32"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := generatedArg(testCase.value, testCase.oscType)
+			errorMsg := ""
+			if err != nil {
+				errorMsg = err.Error()
+			}
+			if errorMsg != testCase.errorMsg {
+				t.Fatalf("failed to convert generated value got error '%s', expected '%s'", errorMsg, testCase.errorMsg)
+			}
+			if err == nil && !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to convert generated value got '%v', expected '%v'", got, testCase.expected)
+			}
+		})
+	}
+}
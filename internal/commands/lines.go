@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+// packetFromLine parses one line of packet JSON or the text syntax
+func packetFromLine(line string) (osc.OSCPacket, error) {
+	if strings.HasPrefix(line, "{") {
+		return osc.PacketFromJSON([]byte(line))
+	}
+	return osc.PacketFromText(line)
+}
+
+// sendLines sends one packet per line of reader over conn, bad lines are reported to errOut and skipped
+func sendLines(reader io.Reader, conn osc.PacketWriter, slip bool, errOut io.Writer) (int, int, error) {
+	sent := 0
+	failed := 0
+
+	scanner := bufio.NewScanner(reader)
+	// NOTE: JSON lines with big blobs can be well over the default 64KB token size
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		packet, err := packetFromLine(line)
+		if err != nil {
+			failed++
+			fmt.Fprintf(errOut, "line %d: %s\n", lineNumber, err)
+			continue
+		}
+
+		if err := writePacket(conn, packet, slip); err != nil {
+			failed++
+			fmt.Fprintf(errOut, "line %d: %s\n", lineNumber, err)
+			continue
+		}
+		sent++
+	}
+	return sent, failed, scanner.Err()
+}
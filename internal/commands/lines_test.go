@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+func TestPacketFromLine(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		line     string
+		expected osc.OSCPacket
+	}{
+		{
+			name:     "text syntax",
+			line:     "/cue/go ,i 12",
+			expected: &osc.OSCMessage{Address: "/cue/go", Args: []osc.OSCArg{{Type: "i", Value: int32(12)}}},
+		},
+		{
+			name:     "packet JSON",
+			line:     `{"address":"/cue/go","args":[{"type":"i","value":12}]}`,
+			expected: &osc.OSCMessage{Address: "/cue/go", Args: []osc.OSCArg{{Type: "i", Value: int32(12)}}},
+		},
+		{
+			name:     "time tag arg",
+			line:     "/cue/at ,t 2024-01-01T00:00:00Z",
+			expected: &osc.OSCMessage{Address: "/cue/at", Args: []osc.OSCArg{{Type: "t", Value: osc.NewOSCTimeTag(3913056000, 0)}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := packetFromLine(testCase.line)
+			if err != nil {
+				t.Fatalf("failed to parse line: %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to parse line got '%v', expected '%v'", got, testCase.expected)
+			}
+			// NOTE: lines are parsed to be sent so they have to encode too
+			_, err = got.ToBytes()
+			if err != nil {
+				t.Fatalf("failed to encode parsed line: %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestBadPacketFromLine(t *testing.T) {
+	for _, line := range []string{"cue/go ,i 1", `{"address":`, "/cue/go ,i one"} {
+		_, err := packetFromLine(line)
+		if err == nil {
+			t.Fatalf("expected line '%s' to fail", line)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	osc "github.com/jwetzell/osc-go"
+	"github.com/urfave/cli/v3"
+)
+
+func Make() *cli.Command {
+	return &cli.Command{
+		Name:  "make",
+		Usage: "make osc bytes",
+		Flags: append(
+			messageFlags("OSC args"),
+			slipFlag("whether to slip encode the OSC Message bytes"),
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: fmt.Sprintf("encoding to write the bytes in (%s), size prefixes them with their length", strings.Join(encodings, ", ")),
+				Value: "raw",
+				Validator: func(flag string) error {
+					if !slices.Contains(encodings, flag) {
+						return fmt.Errorf("output must be one of %s", strings.Join(encodings, ", "))
+					}
+					return nil
+				},
+			},
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if !cmd.IsSet("address") {
+				return errors.New("--address is required")
+			}
+			return makeMsg(cmd.String("address"), cmd.StringSlice("arg"), cmd.StringSlice("type"), cmd.Bool("slip"), cmd.String("output"))
+		},
+	}
+}
+
+func makeMsg(address string, args []string, types []string, slip bool, encoding string) error {
+
+	oscMessage, err := messageFromFlags(address, args, types)
+	if err != nil {
+		return err
+	}
+
+	oscMessageBuffer, err := oscMessage.ToBytes()
+	if err != nil {
+		return err
+	}
+
+	if slip {
+		oscMessageBuffer = osc.SLIPEncode(oscMessageBuffer)
+	}
+
+	encodedBytes, err := encodeBytes(oscMessageBuffer, encoding)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(encodedBytes)
+	return err
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+	"github.com/jwetzell/osc-go/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+func Parse() *cli.Command {
+	return &cli.Command{
+		Name:      "parse",
+		Usage:     "parse OSC bytes from stdin or hex args and print them",
+		ArgsUsage: "[hex...]",
+		Flags: []cli.Flag{
+			formatFlag("format for packets to be output in (%s)"),
+			&cli.BoolFlag{
+				Name:  "hex",
+				Value: false,
+				Usage: "whether stdin is hex text instead of raw bytes",
+			},
+			slipFlag("whether the input is one or more SLIP encoded packets instead of a single packet"),
+			&cli.BoolFlag{
+				Name:  "bundles",
+				Value: false,
+				Usage: "whether to output bundles as a whole instead of flattening them into their messages, hex always writes whole packets",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			var data []byte
+			var err error
+			if cmd.Args().Present() {
+				data, err = hex.DecodeString(strings.Join(strings.Fields(strings.Join(cmd.Args().Slice(), "")), ""))
+			} else {
+				data, err = io.ReadAll(os.Stdin)
+				if err == nil && cmd.Bool("hex") {
+					data, err = hex.DecodeString(strings.Join(strings.Fields(string(data)), ""))
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			out := output.New(os.Stdout, cmd.String("format"), cmd.Bool("bundles"), nil, "stderr")
+			return parsePackets(data, cmd.Bool("slip"), out)
+		},
+	}
+}
+
+func parsePackets(data []byte, slip bool, out *output.Output) error {
+	source := output.Source{
+		Protocol: "stdin",
+		Received: time.Now(),
+	}
+
+	frames := [][]byte{data}
+	if slip {
+		frames = [][]byte{}
+		// NOTE: stray END bytes between or after frames are not packets
+		for len(bytes.Trim(data, "\xc0")) > 0 {
+			frame, rest, err := osc.SLIPDecode(data)
+			if err != nil {
+				return err
+			}
+			if len(frame) > 0 {
+				frames = append(frames, frame)
+			}
+			data = rest
+		}
+	}
+
+	malformed := false
+	for _, frame := range frames {
+		packet, _, err := osc.PacketFromBytes(frame)
+		if err != nil {
+			out.WriteError(frame, err, source)
+			malformed = true
+			continue
+		}
+		out.WritePacket(packet, source)
+	}
+	if malformed {
+		return errors.New("input contained malformed packets")
+	}
+	return nil
+}
@@ -0,0 +1,390 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+	"github.com/jwetzell/osc-go/internal/output"
+	"github.com/urfave/cli/v3"
+)
+
+var receiveProtocols = []string{"udp", "tcp", "unix", "unixgram", "ws", "serial", "stdout"}
+
+func Receive() *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "ip",
+			Usage: "ip to receive OSC messages on (:: listens on both IPv4 and IPv6)",
+			Value: "::",
+		},
+	}
+	flags = append(flags, ipFamilyFlags("listen")...)
+	flags = append(flags,
+		&cli.Int32Flag{
+			Name:  "port",
+			Usage: "port to receive OSC messages on",
+			Value: 8888,
+		},
+		protocolFlag(receiveProtocols, "protocol to use to receive (%s), stdout writes framed packets received over UDP to stdout with filtered messages removed"),
+	)
+	flags = append(flags, localTransportFlags("receive")...)
+	flags = append(flags,
+		formatFlag("format for messages to be output in (%s)"),
+		&cli.BoolFlag{
+			Name:  "bundles",
+			Value: false,
+			Usage: "whether to output bundles as a whole instead of flattening them into their messages, hex always writes whole packets",
+		},
+		&cli.StringSliceFlag{
+			Name:  "include",
+			Usage: "only output messages with an address matching this OSC address pattern (repeatable)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude",
+			Usage: "do not output messages with an address matching this OSC address pattern (repeatable)",
+		},
+		&cli.BoolFlag{
+			Name:  "regex",
+			Value: false,
+			Usage: "whether --include and --exclude are regular expressions instead of OSC address patterns",
+		},
+		&cli.StringFlag{
+			Name:  "errors",
+			Usage: fmt.Sprintf("where to report malformed packets (%s)", strings.Join(output.ErrorModes, ", ")),
+			Value: "stderr",
+			Validator: func(flag string) error {
+				if !slices.Contains(output.ErrorModes, flag) {
+					return fmt.Errorf("errors must be one of %s", strings.Join(output.ErrorModes, ", "))
+				}
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:  "multicast-group",
+			Usage: "UDP multicast group to join and receive OSC messages from",
+		},
+		&cli.StringFlag{
+			Name:  "interface",
+			Usage: "network interface to join the UDP multicast group on",
+		},
+		slipFlag("whether OSC packets on stream protocols and stdout are SLIP encoded instead of size prefixed"),
+		&cli.FloatFlag{
+			Name:  "source-rate",
+			Usage: "most messages per second to accept from each source IP, extra messages are dropped, 0 has no limit",
+		},
+		&cli.IntFlag{
+			Name:  "source-burst",
+			Usage: "most messages a source IP can send at once before --source-rate applies, 0 allows one second's worth",
+		},
+		&cli.StringSliceFlag{
+			Name:  "address-rate",
+			Usage: "PATTERN=RATE, most messages per second to accept across all sources with an address matching the OSC address pattern (repeatable)",
+		},
+		hmacKeyFlag("only accept packets signed with this HMAC-SHA256 key, see --require-signed"),
+		&cli.StringSliceFlag{
+			Name:  "require-signed",
+			Usage: "only require signatures on messages with an address matching this OSC address pattern, others can be unsigned (repeatable, --hmac-key)",
+		},
+		&cli.DurationFlag{
+			Name:  "signature-max-age",
+			Value: osc.DefaultSignatureMaxAge,
+			Usage: "most a signature's time can differ from this host's clock, older signatures are rejected as replays (--hmac-key)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "allow",
+			Usage: "IP or CIDR[=PATTERN], only accept messages from these sources, to addresses matching the OSC address pattern when set (repeatable)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "deny",
+			Usage: "IP or CIDR[=PATTERN], drop messages from these sources, to addresses matching the OSC address pattern when set, deny wins over --allow (repeatable)",
+		},
+	)
+	flags = append(flags, tlsFlags(
+		"PEM certificate file to present to clients (--tls)",
+		"PEM CA file, when set clients must present a certificate signed by it (--tls)",
+	)...)
+
+	return &cli.Command{
+		Name:    "recv",
+		Aliases: []string{"receive"},
+		Usage:   "receive OSC messages via UDP, TCP, WebSocket, unix domain sockets or serial",
+		// NOTE: address patterns use commas in {} string lists
+		DisableSliceFlagSeparator: true,
+		Flags:                     flags,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			ip := cmd.String("ip")
+			port := cmd.Int32("port")
+			protocol := cmd.String("protocol")
+			format := cmd.String("format")
+			filter, err := output.NewFilter(cmd.StringSlice("include"), cmd.StringSlice("exclude"), cmd.Bool("regex"))
+			if err != nil {
+				return err
+			}
+			framing := streamFraming(cmd)
+			out := output.New(os.Stdout, format, cmd.Bool("bundles"), filter, cmd.String("errors"))
+			if protocol == "stdout" {
+				out = output.NewPacketWriter(osc.NewStdioConn(framing), filter, cmd.String("errors"))
+			}
+
+			socketPath := cmd.String("socket")
+			if (protocol == "unix" || protocol == "unixgram") && socketPath == "" {
+				return fmt.Errorf("--socket is required for the %s protocol", protocol)
+			}
+			if cmd.Bool("tls") && protocol != "tcp" {
+				return fmt.Errorf("--tls is only supported for the tcp protocol")
+			}
+			if protocol == "stdout" && cmd.String("errors") == "inline" {
+				// NOTE: inline error records would end up in the middle of the framed packets on stdout
+				return fmt.Errorf("--errors inline cannot be used with the stdout protocol")
+			}
+			if protocol == "serial" && !cmd.IsSet("device") {
+				return fmt.Errorf("--device is required for the %s protocol", protocol)
+			}
+			// NOTE: stdout packets are received over UDP so multicast works for it too
+			for _, name := range []string{"multicast-group", "interface"} {
+				if cmd.IsSet(name) && protocol != "udp" && protocol != "stdout" {
+					return fmt.Errorf("--%s is only supported for the udp protocol", name)
+				}
+			}
+
+			ipv4 := cmd.Bool("ipv4")
+			ipv6 := cmd.Bool("ipv6")
+			if ipv4 && ipv6 {
+				return fmt.Errorf("--ipv4 and --ipv6 cannot be used together")
+			}
+			if (ipv4 || ipv6) && protocol != "udp" && protocol != "tcp" && protocol != "stdout" {
+				return fmt.Errorf("--ipv4 and --ipv6 are only supported for the udp and tcp protocols")
+			}
+			if ipv4 && !cmd.IsSet("ip") {
+				ip = "0.0.0.0"
+			}
+
+			server := newServer(protocol, framing, out)
+			handler, guards, err := guard(cmd, server.Handler)
+			if err != nil {
+				return err
+			}
+			server.Handler = handler
+
+			signals := make(chan os.Signal, 1)
+			signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+			go func() {
+				<-signals
+				out.WriteSummary(os.Stderr)
+				guards.writeSummary(os.Stderr)
+				if socketPath != "" {
+					os.Remove(socketPath)
+				}
+				os.Exit(0)
+			}()
+
+			netAddress := net.JoinHostPort(strings.Trim(ip, "[]"), fmt.Sprintf("%d", port))
+			switch protocol {
+			case "udp", "stdout":
+				udpConfig := osc.UDPConfig{}
+				if cmd.IsSet("interface") {
+					ifi, err := net.InterfaceByName(cmd.String("interface"))
+					if err != nil {
+						return err
+					}
+					udpConfig.MulticastInterface = ifi
+				}
+				var conn *osc.UDPConn
+				if cmd.IsSet("multicast-group") {
+					multicastGroup := net.JoinHostPort(strings.Trim(cmd.String("multicast-group"), "[]"), fmt.Sprintf("%d", port))
+					conn, err = udpConfig.ListenMulticast(network("udp", ipv4, ipv6), multicastGroup)
+				} else {
+					conn, err = udpConfig.Listen(network("udp", ipv4, ipv6), netAddress)
+				}
+				if err != nil {
+					return err
+				}
+				return server.Serve(conn)
+			case "tcp":
+				if cmd.Bool("tls") {
+					tlsConfig, err := serverTLSConfig(cmd.String("cert"), cmd.String("key"), cmd.String("ca"))
+					if err != nil {
+						return err
+					}
+					return server.ListenAndServeTLS(network(protocol, ipv4, ipv6), netAddress, tlsConfig)
+				}
+				return server.ListenAndServe(network(protocol, ipv4, ipv6), netAddress)
+			case "ws":
+				return server.ListenAndServe(protocol, netAddress)
+			case "unix", "unixgram":
+				return server.ListenAndServe(protocol, socketPath)
+			case "serial":
+				conn, err := osc.OpenSerial(cmd.String("device"), cmd.Int("baud"))
+				if err != nil {
+					return err
+				}
+				return server.Serve(conn)
+			}
+			return nil
+		},
+	}
+}
+
+func newServer(protocol string, framing osc.Framing, out *output.Output) *osc.Server {
+	return &osc.Server{
+		Framing: framing,
+		Handler: osc.HandlerFunc(func(w osc.PacketWriter, packet osc.OSCPacket, source net.Addr) {
+			out.WritePacket(packet, output.Source{
+				Protocol: protocol,
+				Address:  source,
+				Received: time.Now(),
+			})
+		}),
+		ErrorHandler: func(err error, source net.Addr) {
+			var decodeErr *osc.DecodeError
+			if errors.As(err, &decodeErr) {
+				out.WriteError(decodeErr.Bytes, decodeErr.Err, output.Source{
+					Protocol: protocol,
+					Address:  source,
+					Received: time.Now(),
+				})
+				return
+			}
+			fmt.Fprintln(os.Stderr, err)
+		},
+	}
+}
+
+// guards are the checks recv puts in front of its output, each is nil when its flags aren't set
+type guards struct {
+	verifier *osc.Verifier
+	limiter  *osc.RateLimiter
+	acl      *osc.AccessList
+}
+
+// guard wraps handler in the signature check, rate limits and access list from the flags and returns the outermost handler
+func guard(cmd *cli.Command, handler osc.Handler) (osc.Handler, guards, error) {
+	g := guards{}
+	var err error
+
+	g.verifier, err = signatureVerifier(cmd, handler)
+	if err != nil {
+		return nil, guards{}, err
+	}
+	if g.verifier != nil {
+		handler = g.verifier
+	}
+	// NOTE: the rate limiter goes outside the verifier so a flood of forged packets is limited before each costs an HMAC
+	g.limiter, err = rateLimiter(cmd, handler)
+	if err != nil {
+		return nil, guards{}, err
+	}
+	if g.limiter != nil {
+		handler = g.limiter
+	}
+	// NOTE: the access list goes outermost so denied sources don't use up rate limits
+	g.acl, err = accessList(cmd, handler)
+	if err != nil {
+		return nil, guards{}, err
+	}
+	if g.acl != nil {
+		handler = g.acl
+	}
+	return handler, g, nil
+}
+
+// writeSummary reports what each guard dropped
+func (g guards) writeSummary(w io.Writer) {
+	if g.limiter != nil {
+		stats := g.limiter.Stats()
+		fmt.Fprintf(w, "dropped %d messages over the source rate and %d over address rates\n", stats.DroppedBySource, stats.DroppedByPattern)
+	}
+	if g.verifier != nil {
+		fmt.Fprintf(w, "rejected %d unsigned or badly signed packets\n", g.verifier.Rejected())
+	}
+	if g.acl != nil {
+		fmt.Fprintf(w, "denied %d messages by --allow and --deny\n", g.acl.Denied())
+	}
+}
+
+// rateLimiter wraps handler with the --source-rate and --address-rate limits or returns nil if there are none
+func rateLimiter(cmd *cli.Command, handler osc.Handler) (*osc.RateLimiter, error) {
+	if !cmd.IsSet("source-rate") && !cmd.IsSet("address-rate") {
+		return nil, nil
+	}
+	if cmd.Float("source-rate") < 0 {
+		return nil, fmt.Errorf("--source-rate cannot be negative")
+	}
+
+	limiter := osc.NewRateLimiter(handler, osc.RateLimit{
+		Rate:  cmd.Float("source-rate"),
+		Burst: cmd.Int("source-burst"),
+	})
+	for _, addressRate := range cmd.StringSlice("address-rate") {
+		pattern, rawRate, ok := strings.Cut(addressRate, "=")
+		if !ok {
+			return nil, fmt.Errorf("--address-rate must be PATTERN=RATE: %s", addressRate)
+		}
+		rate, err := strconv.ParseFloat(rawRate, 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("--address-rate has an invalid rate: %s", addressRate)
+		}
+		if err := limiter.LimitPattern(pattern, osc.RateLimit{Rate: rate}); err != nil {
+			return nil, err
+		}
+	}
+	return limiter, nil
+}
+
+// accessList wraps handler with the --allow and --deny rules or returns nil if there are none
+func accessList(cmd *cli.Command, handler osc.Handler) (*osc.AccessList, error) {
+	if !cmd.IsSet("allow") && !cmd.IsSet("deny") {
+		return nil, nil
+	}
+
+	accessList := osc.NewAccessList(handler)
+	for _, allow := range cmd.StringSlice("allow") {
+		cidr, pattern, _ := strings.Cut(allow, "=")
+		if err := accessList.Allow(cidr, pattern); err != nil {
+			return nil, fmt.Errorf("--allow %s: %w", allow, err)
+		}
+	}
+	for _, deny := range cmd.StringSlice("deny") {
+		cidr, pattern, _ := strings.Cut(deny, "=")
+		if err := accessList.Deny(cidr, pattern); err != nil {
+			return nil, fmt.Errorf("--deny %s: %w", deny, err)
+		}
+	}
+	return accessList, nil
+}
+
+// signatureVerifier wraps handler with a check of --hmac-key signatures or returns nil if there is no key
+func signatureVerifier(cmd *cli.Command, handler osc.Handler) (*osc.Verifier, error) {
+	if !cmd.IsSet("hmac-key") {
+		if cmd.IsSet("require-signed") {
+			return nil, fmt.Errorf("--require-signed needs --hmac-key")
+		}
+		return nil, nil
+	}
+	if cmd.String("hmac-key") == "" {
+		return nil, fmt.Errorf("--hmac-key cannot be empty")
+	}
+	if cmd.Duration("signature-max-age") <= 0 {
+		return nil, fmt.Errorf("--signature-max-age must be greater than 0")
+	}
+
+	verifier := osc.NewVerifier(handler, []byte(cmd.String("hmac-key")))
+	verifier.MaxAge = cmd.Duration("signature-max-age")
+	for _, pattern := range cmd.StringSlice("require-signed") {
+		if err := verifier.Require(pattern); err != nil {
+			return nil, fmt.Errorf("--require-signed %s: %w", pattern, err)
+		}
+	}
+	return verifier, nil
+}
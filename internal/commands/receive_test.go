@@ -0,0 +1,279 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	osc "github.com/jwetzell/osc-go"
+	"github.com/urfave/cli/v3"
+)
+
+// withReceiveFlags parses args with the recv flags and calls action with the parsed command
+func withReceiveFlags(args []string, action func(cmd *cli.Command) error) error {
+	cmd := &cli.Command{
+		Name:                      "recv",
+		Flags:                     Receive().Flags,
+		DisableSliceFlagSeparator: true,
+		Writer:                    io.Discard,
+		ErrWriter:                 io.Discard,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			return action(cmd)
+		},
+	}
+	return cmd.Run(context.Background(), append([]string{"recv"}, args...))
+}
+
+func TestAccessListFlags(t *testing.T) {
+	foh := &net.UDPAddr{IP: net.IPv4(10, 0, 1, 20), Port: 9000}
+	stage := &net.UDPAddr{IP: net.IPv4(10, 0, 2, 30), Port: 9000}
+
+	type check struct {
+		source  net.Addr
+		address string
+		allowed bool
+	}
+
+	testCases := []struct {
+		name     string
+		args     []string
+		none     bool
+		checks   []check
+		errorMsg string
+	}{
+		{name: "no flags", args: []string{}, none: true},
+		{
+			name: "allow with a pattern",
+			args: []string{"--allow", "10.0.1.0/24=/master/*"},
+			checks: []check{
+				{source: foh, address: "/master/fader", allowed: true},
+				{source: stage, address: "/master/fader", allowed: false},
+				{source: stage, address: "/ch/1", allowed: true},
+			},
+		},
+		{
+			name: "deny",
+			args: []string{"--deny", "10.0.2.30"},
+			checks: []check{
+				{source: foh, address: "/ch/1", allowed: true},
+				{source: stage, address: "/ch/1", allowed: false},
+			},
+		},
+		{name: "bad allow", args: []string{"--allow", "10.0.1"}, errorMsg: "--allow 10.0.1: invalid IP or CIDR: 10.0.1"},
+		{name: "bad deny pattern", args: []string{"--deny", "10.0.1.0/24=master"}, errorMsg: "--deny 10.0.1.0/24=master: OSC address pattern must start with /"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := withReceiveFlags(testCase.args, func(cmd *cli.Command) error {
+				acl, err := accessList(cmd, nil)
+				if err != nil {
+					return err
+				}
+				if (acl == nil) != testCase.none {
+					t.Fatalf("failed to build access list got %v", acl)
+				}
+				for _, check := range testCase.checks {
+					if acl.Allowed(check.source, check.address) != check.allowed {
+						t.Fatalf("failed to check %s to %s got %t, expected %t", check.source, check.address, !check.allowed, check.allowed)
+					}
+				}
+				return nil
+			})
+			errorMsg := ""
+			if err != nil {
+				errorMsg = err.Error()
+			}
+			if errorMsg != testCase.errorMsg {
+				t.Fatalf("failed to parse flags got '%s', expected '%s'", errorMsg, testCase.errorMsg)
+			}
+		})
+	}
+}
+
+func TestRateLimiterFlags(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		args     []string
+		none     bool
+		errorMsg string
+	}{
+		{name: "no flags", args: []string{}, none: true},
+		{name: "source rate", args: []string{"--source-rate", "10", "--source-burst", "5"}},
+		{name: "address rate", args: []string{"--address-rate", "/touch/*=20"}},
+		{name: "negative source rate", args: []string{"--source-rate", "-1"}, errorMsg: "--source-rate cannot be negative"},
+		{name: "address rate without a rate", args: []string{"--address-rate", "/touch/*"}, errorMsg: "--address-rate must be PATTERN=RATE: /touch/*"},
+		{name: "address rate with a bad rate", args: []string{"--address-rate", "/touch/*=0"}, errorMsg: "--address-rate has an invalid rate: /touch/*=0"},
+		{name: "address rate with a bad pattern", args: []string{"--address-rate", "touch=1"}, errorMsg: "OSC address pattern must start with /"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := withReceiveFlags(testCase.args, func(cmd *cli.Command) error {
+				limiter, err := rateLimiter(cmd, nil)
+				if err != nil {
+					return err
+				}
+				if (limiter == nil) != testCase.none {
+					t.Fatalf("failed to build rate limiter got %v", limiter)
+				}
+				return nil
+			})
+			errorMsg := ""
+			if err != nil {
+				errorMsg = err.Error()
+			}
+			if errorMsg != testCase.errorMsg {
+				t.Fatalf("failed to parse flags got '%s', expected '%s'", errorMsg, testCase.errorMsg)
+			}
+		})
+	}
+}
+
+func TestSignatureVerifierFlags(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		args     []string
+		none     bool
+		required map[string]bool
+		errorMsg string
+	}{
+		{name: "no flags", args: []string{}, none: true},
+		{
+			name:     "every address",
+			args:     []string{"--hmac-key", "secret"},
+			required: map[string]bool{"/cue/go": true, "/meter/1": true},
+		},
+		{
+			name:     "required patterns",
+			args:     []string{"--hmac-key", "secret", "--require-signed", "/cue/*"},
+			required: map[string]bool{"/cue/go": true, "/meter/1": false},
+		},
+		{name: "require without a key", args: []string{"--require-signed", "/cue/*"}, errorMsg: "--require-signed needs --hmac-key"},
+		{name: "empty key", args: []string{"--hmac-key", ""}, errorMsg: "--hmac-key cannot be empty"},
+		{name: "no max age", args: []string{"--hmac-key", "secret", "--signature-max-age", "0s"}, errorMsg: "--signature-max-age must be greater than 0"},
+		{name: "bad pattern", args: []string{"--hmac-key", "secret", "--require-signed", "cue"}, errorMsg: "--require-signed cue: OSC address pattern must start with /"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := withReceiveFlags(testCase.args, func(cmd *cli.Command) error {
+				verifier, err := signatureVerifier(cmd, nil)
+				if err != nil {
+					return err
+				}
+				if (verifier == nil) != testCase.none {
+					t.Fatalf("failed to build verifier got %v", verifier)
+				}
+				for address, required := range testCase.required {
+					if verifier.Required(address) != required {
+						t.Fatalf("failed to require %s got %t, expected %t", address, !required, required)
+					}
+				}
+				return nil
+			})
+			errorMsg := ""
+			if err != nil {
+				errorMsg = err.Error()
+			}
+			if errorMsg != testCase.errorMsg {
+				t.Fatalf("failed to parse flags got '%s', expected '%s'", errorMsg, testCase.errorMsg)
+			}
+		})
+	}
+}
+
+func TestGuardSignedRateLimit(t *testing.T) {
+	key := []byte("secret")
+	source := &net.UDPAddr{IP: net.IPv4(10, 0, 1, 20), Port: 9000}
+	cue := &osc.OSCMessage{Address: "/cue/go", Args: []osc.OSCArg{{Type: "i", Value: int32(1)}}}
+
+	received := 0
+	err := withReceiveFlags([]string{"--hmac-key", string(key), "--source-rate", "1"}, func(cmd *cli.Command) error {
+		handler, guards, err := guard(cmd, osc.HandlerFunc(func(w osc.PacketWriter, packet osc.OSCPacket, source net.Addr) {
+			received++
+		}))
+		if err != nil {
+			return err
+		}
+
+		for index := 0; index < 2; index++ {
+			signed, err := osc.NewSigner(nil, key).Sign(cue)
+			if err != nil {
+				return err
+			}
+			signedBytes, err := signed.ToBytes()
+			if err != nil {
+				return err
+			}
+			packet, _, err := osc.PacketFromBytes(signedBytes)
+			if err != nil {
+				return err
+			}
+			handler.ServeOSC(nil, packet, source)
+		}
+
+		if received != 1 {
+			t.Fatalf("failed to receive signed packets got %d, expected 1", received)
+		}
+		if guards.verifier.Rejected() != 0 {
+			t.Fatalf("failed to verify signed packets got %d rejected, expected 0", guards.verifier.Rejected())
+		}
+		stats := guards.limiter.Stats()
+		if stats.Allowed != 1 || stats.DroppedBySource != 1 {
+			t.Fatalf("failed to rate limit signed packets got '%+v', expected 1 allowed and 1 dropped", stats)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to parse flags: %s", err.Error())
+	}
+}
+
+func TestBadReceiveFlags(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		args     []string
+		errorMsg string
+	}{
+		{
+			name:     "multicast group over tcp",
+			args:     []string{"--protocol", "tcp", "--multicast-group", "239.255.0.1"},
+			errorMsg: "--multicast-group is only supported for the udp protocol",
+		},
+		{
+			name:     "multicast interface over ws",
+			args:     []string{"--protocol", "ws", "--interface", "lo"},
+			errorMsg: "--interface is only supported for the udp protocol",
+		},
+		{
+			name:     "ipv6 over ws",
+			args:     []string{"--protocol", "ws", "--ipv6"},
+			errorMsg: "--ipv4 and --ipv6 are only supported for the udp and tcp protocols",
+		},
+		{
+			name:     "ipv4 over unix",
+			args:     []string{"--protocol", "unix", "--socket", "/tmp/osc-test.sock", "--ipv4"},
+			errorMsg: "--ipv4 and --ipv6 are only supported for the udp and tcp protocols",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			cmd := Receive()
+			cmd.Writer = io.Discard
+			cmd.ErrWriter = io.Discard
+			err := cmd.Run(context.Background(), append([]string{"recv", "--port", "0"}, testCase.args...))
+			if err == nil {
+				t.Fatalf("expected flags to fail")
+			}
+			if err.Error() != testCase.errorMsg {
+				t.Fatalf("failed to reject flags got '%s', expected '%s'", err.Error(), testCase.errorMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+	"github.com/jwetzell/osc-go/internal/output"
+)
+
+type readResult struct {
+	packet osc.OSCPacket
+	source net.Addr
+	err    error
+}
+
+// waitReplies writes packets read from conn that pass filter until count have arrived or timeout passes without one, count 0 waits out the timeout
+func waitReplies(conn osc.Conn, protocol string, out *output.Output, filter *output.Filter, count int, timeout time.Duration) (int, error) {
+	results := make(chan readResult)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			packet, source, err := conn.ReadPacket()
+			select {
+			case results <- readResult{packet: packet, source: source, err: err}:
+			case <-done:
+				return
+			}
+			var decodeErr *osc.DecodeError
+			if err != nil && !errors.As(err, &decodeErr) {
+				return
+			}
+		}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	received := 0
+	for {
+		select {
+		case <-timer.C:
+			if received == 0 {
+				return 0, fmt.Errorf("no reply within %s", timeout)
+			}
+			return received, nil
+		case result := <-results:
+			source := output.Source{
+				Protocol: protocol,
+				Address:  result.source,
+				Received: time.Now(),
+			}
+			if result.err != nil {
+				var decodeErr *osc.DecodeError
+				if errors.As(result.err, &decodeErr) {
+					out.WriteError(decodeErr.Bytes, decodeErr.Err, source)
+					continue
+				}
+				if errors.Is(result.err, io.EOF) && received > 0 {
+					return received, nil
+				}
+				return received, result.err
+			}
+
+			reply := filter.Prune(result.packet)
+			if reply == nil {
+				continue
+			}
+			out.WritePacket(reply, source)
+			received++
+			if count > 0 && received >= count {
+				return received, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+	"github.com/jwetzell/osc-go/internal/output"
+)
+
+func TestWaitReplies(t *testing.T) {
+	listener, err := osc.ListenUDP("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	server := &osc.Server{Handler: osc.HandlerFunc(func(w osc.PacketWriter, packet osc.OSCPacket, source net.Addr) {
+		w.WritePacket(&osc.OSCMessage{Address: "/meter/1", Args: []osc.OSCArg{}})
+		w.WritePacket(&osc.OSCMessage{Address: "/reply", Args: []osc.OSCArg{{Type: "i", Value: int32(1)}}})
+		w.WritePacket(&osc.OSCMessage{Address: "/reply", Args: []osc.OSCArg{{Type: "i", Value: int32(2)}}})
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	firstReply := `{"address":"/reply","args":[{"value":1,"type":"i"}]}` + "\n"
+	secondReply := `{"address":"/reply","args":[{"value":2,"type":"i"}]}` + "\n"
+
+	testCases := []struct {
+		name     string
+		include  []string
+		count    int
+		received int
+		expected string
+		errorMsg string
+	}{
+		{
+			name:     "first reply",
+			include:  []string{"/reply"},
+			count:    1,
+			received: 1,
+			expected: firstReply,
+		},
+		{
+			name:     "until the timeout",
+			include:  []string{"/reply"},
+			count:    0,
+			received: 2,
+			expected: firstReply + secondReply,
+		},
+		{
+			name:     "fewer replies than the count",
+			include:  []string{"/reply"},
+			count:    5,
+			received: 2,
+			expected: firstReply + secondReply,
+		},
+		{
+			name:     "every reply filtered",
+			include:  []string{"/nothing"},
+			count:    1,
+			received: 0,
+			errorMsg: "no reply within 200ms",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			conn, err := osc.DialUDP("udp4", listener.LocalAddr().String())
+			if err != nil {
+				t.Fatalf("failed to dial: %s", err.Error())
+			}
+			defer conn.Close()
+
+			filter, err := output.NewFilter(testCase.include, []string{}, false)
+			if err != nil {
+				t.Fatalf("failed to create filter: %s", err.Error())
+			}
+			var buffer bytes.Buffer
+			out := output.New(&buffer, "json", false, filter, "stderr")
+
+			err = conn.WritePacket(&osc.OSCMessage{Address: "/query", Args: []osc.OSCArg{}})
+			if err != nil {
+				t.Fatalf("failed to write packet: %s", err.Error())
+			}
+
+			received, err := waitReplies(conn, "udp", out, filter, testCase.count, 200*time.Millisecond)
+			errorMsg := ""
+			if err != nil {
+				errorMsg = err.Error()
+			}
+			if errorMsg != testCase.errorMsg {
+				t.Fatalf("failed to wait for replies got error '%s', expected '%s'", errorMsg, testCase.errorMsg)
+			}
+			if received != testCase.received {
+				t.Fatalf("failed to count replies got %d, expected %d", received, testCase.received)
+			}
+			if buffer.String() != testCase.expected {
+				t.Fatalf("failed to write replies got '%s', expected '%s'", buffer.String(), testCase.expected)
+			}
+		})
+	}
+}
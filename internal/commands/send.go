@@ -0,0 +1,571 @@
+package commands
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+	"github.com/jwetzell/osc-go/internal/output"
+
+	"github.com/urfave/cli/v3"
+)
+
+var sendProtocols = []string{"udp", "tcp", "unix", "unixgram", "ws", "serial", "stdin"}
+
+func Send() *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "host",
+			Usage: "host to send OSC message to",
+		},
+		&cli.Int32Flag{
+			Name:  "port",
+			Usage: "port to send OSC message to",
+		},
+		protocolFlag(sendProtocols, "protocol to use to send (%s), stdin forwards framed packets read from stdin over UDP"),
+	}
+	flags = append(flags, localTransportFlags("send")...)
+	flags = append(flags, messageFlags("OSC args, numeric types also take generators (ramp:FROM:TO:DURATION, sine:MIN:MAX:PERIOD, triangle:MIN:MAX:PERIOD, random:MIN:MAX, step:VALUE:VALUE...)")...)
+	flags = append(flags,
+		&cli.BoolFlag{
+			Name:  "stdin",
+			Value: false,
+			Usage: "whether to send one message or bundle per line of stdin, as packet JSON or the text syntax, over a single connection",
+		},
+		&cli.IntFlag{
+			Name:  "count",
+			Value: 1,
+			Usage: "number of messages to send, 0 sends until interrupted",
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "time between messages when --count is not 1",
+		},
+		&cli.FloatFlag{
+			Name:  "rate",
+			Usage: "messages per second when --count is not 1, instead of --interval",
+		},
+		&cli.BoolFlag{
+			Name:  "wait-reply",
+			Value: false,
+			Usage: "whether to wait for and print replies on the same socket or connection after sending",
+		},
+		&cli.DurationFlag{
+			Name:  "reply-timeout",
+			Value: 2 * time.Second,
+			Usage: "how long to wait for a reply (--wait-reply)",
+		},
+		&cli.StringFlag{
+			Name:  "reply-address",
+			Usage: "only print replies with an address matching this OSC address pattern (--wait-reply)",
+		},
+		&cli.IntFlag{
+			Name:  "replies",
+			Value: 1,
+			Usage: "number of replies to wait for, 0 prints replies until the timeout (--wait-reply)",
+		},
+		formatFlag("format for replies to be output in (%s)"),
+		&cli.StringFlag{
+			Name:  "path",
+			Value: "/",
+			Usage: "URL path of the WebSocket endpoint (ws protocol)",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Value: false,
+			Usage: "whether to send the packet JSON in a text frame instead of OSC bytes in a binary frame (ws protocol)",
+		},
+	)
+	flags = append(flags, ipFamilyFlags("send")...)
+	flags = append(flags,
+		&cli.BoolFlag{
+			Name:  "broadcast",
+			Value: false,
+			Usage: "whether to allow sending to a UDP broadcast address",
+		},
+		&cli.StringFlag{
+			Name:  "interface",
+			Usage: "network interface to send UDP multicast from",
+		},
+		&cli.IntFlag{
+			Name:  "multicast-ttl",
+			Value: 1,
+			Usage: "TTL of UDP multicast packets",
+		},
+		&cli.BoolFlag{
+			Name:  "multicast-loopback",
+			Value: true,
+			Usage: "whether UDP multicast packets are also delivered to this host",
+		},
+		&cli.IntFlag{
+			Name:  "max-packet-size",
+			Usage: "split UDP bundles bigger than this many bytes and reject messages that are, 0 has no limit",
+		},
+		slipFlag("whether to slip encode the OSC Message bytes instead of size prefixing them on stream protocols and stdin"),
+	)
+	flags = append(flags, tlsFlags(
+		"PEM client certificate file for servers that require one (--tls)",
+		"PEM CA file to verify the server with instead of the system roots (--tls)",
+	)...)
+	flags = append(flags, &cli.BoolFlag{
+		Name:  "insecure-skip-verify",
+		Value: false,
+		Usage: "whether to skip verifying the server certificate, only for testing (--tls)",
+	})
+
+	flags = append(flags, &cli.DurationFlag{
+		Name:  "bundle-window",
+		Usage: "collect messages sent within this long of each other into one bundle",
+	}, &cli.IntFlag{
+		Name:  "bundle-size",
+		Value: osc.DefaultBundleSize,
+		Usage: "most bytes in a bundle before it is sent early, 0 has no limit (--bundle-window)",
+	}, &cli.DurationFlag{
+		Name:  "bundle-delay",
+		Usage: "time tag bundles this far after they are sent instead of immediate (--bundle-window)",
+	}, &cli.StringSliceFlag{
+		Name:  "to",
+		Usage: "endpoint to send to instead of --host and --port, repeat it to send every packet to several at once (protocol://address?options as in osc bridge), the UDP flags apply to udp targets",
+	}, hmacKeyFlag("sign every packet with this HMAC-SHA256 key so receivers with the same --hmac-key can verify it"))
+
+	return &cli.Command{
+		Name:  "send",
+		Usage: "send OSC messages via UDP, TCP, WebSocket, unix domain sockets or serial",
+		Flags: flags,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			address := cmd.String("address")
+			args := cmd.StringSlice("arg")
+			types := cmd.StringSlice("type")
+			slip := cmd.Bool("slip")
+
+			var netAddress, protocol string
+			var err error
+			if cmd.IsSet("to") {
+				if cmd.IsSet("host") || cmd.IsSet("port") || cmd.IsSet("socket") || cmd.IsSet("device") {
+					return fmt.Errorf("--to cannot be used with --host, --port, --socket or --device")
+				}
+				// NOTE: these are set per target with endpoint options like ?slip and ?baud= instead
+				for _, name := range []string{"protocol", "ipv4", "ipv6", "slip", "json", "baud", "tls", "path"} {
+					if cmd.IsSet(name) {
+						return fmt.Errorf("--%s cannot be used with --to, use endpoint options instead", name)
+					}
+				}
+				if cmd.Bool("wait-reply") {
+					return fmt.Errorf("--wait-reply cannot be used with --to")
+				}
+				if !cmd.Bool("stdin") && !cmd.IsSet("address") {
+					return fmt.Errorf("--address is required")
+				}
+			} else {
+				netAddress, protocol, err = target(cmd)
+				if err != nil {
+					return err
+				}
+				if !strings.HasPrefix(protocol, "stdin") && !cmd.Bool("stdin") && !cmd.IsSet("address") {
+					return fmt.Errorf("--address is required for the %s protocol", protocol)
+				}
+			}
+
+			if cmd.IsSet("hmac-key") {
+				if cmd.String("hmac-key") == "" {
+					return fmt.Errorf("--hmac-key cannot be empty")
+				}
+				if strings.HasPrefix(protocol, "stdin") {
+					return fmt.Errorf("--hmac-key cannot be used with the %s protocol", protocol)
+				}
+				// NOTE: splitting a signed bundle separates the packet from its signature
+				if cmd.IsSet("max-packet-size") {
+					return fmt.Errorf("--max-packet-size cannot be used with --hmac-key")
+				}
+			}
+
+			udpConfig := osc.UDPConfig{
+				Broadcast:                cmd.Bool("broadcast"),
+				MulticastTTL:             cmd.Int("multicast-ttl"),
+				DisableMulticastLoopback: !cmd.Bool("multicast-loopback"),
+				MaxPacketSize:            cmd.Int("max-packet-size"),
+			}
+			if cmd.IsSet("interface") {
+				ifi, err := net.InterfaceByName(cmd.String("interface"))
+				if err != nil {
+					return err
+				}
+				udpConfig.MulticastInterface = ifi
+			}
+			var tlsConfig *tls.Config
+			if cmd.Bool("tls") {
+				if !strings.HasPrefix(protocol, "tcp") {
+					return fmt.Errorf("--tls is only supported for the tcp protocol")
+				}
+				tlsConfig, err = clientTLSConfig(strings.Trim(cmd.String("host"), "[]"), cmd.String("cert"), cmd.String("key"), cmd.String("ca"), cmd.Bool("insecure-skip-verify"))
+				if err != nil {
+					return err
+				}
+			}
+
+			if strings.HasPrefix(protocol, "stdin") {
+				conn, err := dial(netAddress, strings.Replace(protocol, "stdin", "udp", 1), false, udpConfig, nil, false, 0)
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+				return forward(osc.NewStdioConn(streamFraming(cmd)), conn, false)
+			}
+
+			var replyFilter *output.Filter
+			if cmd.IsSet("reply-address") {
+				replyFilter, err = output.NewFilter([]string{cmd.String("reply-address")}, []string{}, false)
+				if err != nil {
+					return err
+				}
+			}
+			var conn osc.Conn
+			var targets *osc.FanOut
+			var writer osc.PacketWriter
+			open := func() error {
+				if cmd.IsSet("to") {
+					targets, err = dialTargets(cmd.StringSlice("to"), udpConfig)
+					if err != nil {
+						return err
+					}
+					writer = tolerantWriter{targets: targets, errOut: os.Stderr}
+					return nil
+				}
+				conn, err = dial(netAddress, protocol, slip, udpConfig, tlsConfig, cmd.Bool("json"), cmd.Int("baud"))
+				if err != nil {
+					return err
+				}
+				writer = packetWriterFunc(func(packet osc.OSCPacket) error {
+					return writePacket(conn, packet, slip)
+				})
+				return nil
+			}
+			var bundler *osc.Bundler
+			openBundled := func() error {
+				if err := open(); err != nil {
+					return err
+				}
+				if cmd.IsSet("hmac-key") {
+					writer = osc.NewSigner(writer, []byte(cmd.String("hmac-key")))
+				}
+				if cmd.IsSet("bundle-window") || cmd.IsSet("bundle-size") {
+					bundler = osc.NewBundler(writer, cmd.Duration("bundle-window"))
+					bundler.MaxSize = cmd.Int("bundle-size")
+					if cmd.IsSet("hmac-key") && bundler.MaxSize > 0 {
+						// NOTE: bundles are signed after they are collected so leave room for the signature
+						bundler.MaxSize -= osc.SignatureOverhead
+						if bundler.MaxSize <= 16 {
+							return fmt.Errorf("--bundle-size is too small to fit a signature")
+						}
+					}
+					if delay := cmd.Duration("bundle-delay"); delay > 0 {
+						bundler.TimeTag = func() osc.OSCTimeTag {
+							return osc.TimeTagFromTime(time.Now().Add(delay))
+						}
+					}
+					bundler.ErrorHandler = func(err error) {
+						fmt.Fprintln(os.Stderr, err)
+					}
+					writer = bundler
+				}
+				return nil
+			}
+			closeWriter := func() {
+				if targets != nil {
+					targets.Close()
+				} else if conn != nil {
+					conn.Close()
+				}
+			}
+			finish := func() error {
+				if bundler != nil {
+					if err := bundler.Flush(); err != nil {
+						return err
+					}
+				}
+				if targets == nil {
+					return nil
+				}
+				return summarizeTargets(os.Stderr, targets.Stats())
+			}
+			replies := func() error {
+				if !cmd.Bool("wait-reply") {
+					return nil
+				}
+				out := output.New(os.Stdout, cmd.String("format"), false, replyFilter, "stderr")
+				_, err := waitReplies(conn, protocol, out, replyFilter, cmd.Int("replies"), cmd.Duration("reply-timeout"))
+				return err
+			}
+
+			if cmd.Bool("stdin") {
+				if err := openBundled(); err != nil {
+					return err
+				}
+				defer closeWriter()
+
+				sent, failed, err := sendLines(os.Stdin, writer, slip, os.Stderr)
+				fmt.Fprintf(os.Stderr, "sent %d packets, %d failed\n", sent, failed)
+				if err != nil {
+					return err
+				}
+				if err := finish(); err != nil {
+					return err
+				}
+				if err := replies(); err != nil {
+					return err
+				}
+				if failed > 0 {
+					return fmt.Errorf("%d lines could not be sent", failed)
+				}
+				return nil
+			}
+
+			count := cmd.Int("count")
+			interval := cmd.Duration("interval")
+			if cmd.IsSet("rate") {
+				if cmd.IsSet("interval") {
+					return fmt.Errorf("--interval and --rate cannot be used together")
+				}
+				if cmd.Float("rate") <= 0 {
+					return fmt.Errorf("--rate must be greater than 0")
+				}
+				interval = time.Duration(float64(time.Second) / cmd.Float("rate"))
+			}
+			if count < 0 {
+				return fmt.Errorf("--count cannot be negative")
+			}
+			if count != 1 && interval == 0 {
+				// NOTE: without an interval every packet is sent at offset 0 and would get the same value
+				for index, arg := range args {
+					// NOTE: only numeric args are generators, a string arg can say ramp:0:1:1s
+					numeric := index < len(types) && slices.Contains([]string{"i", "h", "f", "d"}, types[index])
+					if numeric && timedGenerator(arg) {
+						return fmt.Errorf("%s needs --interval or --rate to change between packets", arg)
+					}
+				}
+			}
+
+			if err := openBundled(); err != nil {
+				return err
+			}
+			defer closeWriter()
+
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			start := time.Now()
+			sent, err := send(ctx, writer, address, args, types, slip, count, interval)
+			if count != 1 {
+				elapsed := time.Since(start)
+				fmt.Fprintf(os.Stderr, "sent %d packets in %s (%.1f packets/s)\n", sent, elapsed.Round(time.Millisecond), float64(sent)/elapsed.Seconds())
+			}
+			if err != nil {
+				return err
+			}
+			if err := finish(); err != nil {
+				return err
+			}
+			return replies()
+		},
+	}
+}
+
+// target resolves the address to dial from the transport flags, the protocol gets a 4 or 6 suffix for --ipv4 and --ipv6
+func target(cmd *cli.Command) (string, string, error) {
+	host := cmd.String("host")
+	port := cmd.Int32("port")
+	protocol := cmd.String("protocol")
+
+	var netAddress string
+	if protocol == "unix" || protocol == "unixgram" {
+		if !cmd.IsSet("socket") {
+			return "", "", fmt.Errorf("--socket is required for the %s protocol", protocol)
+		}
+		netAddress = cmd.String("socket")
+	} else if protocol == "serial" {
+		if !cmd.IsSet("device") {
+			return "", "", fmt.Errorf("--device is required for the %s protocol", protocol)
+		}
+		netAddress = cmd.String("device")
+	} else {
+		if !cmd.IsSet("host") || !cmd.IsSet("port") {
+			return "", "", fmt.Errorf("--host and --port are required for the %s protocol", protocol)
+		}
+		// NOTE: accept IPv6 literals with or without brackets
+		netAddress = net.JoinHostPort(strings.Trim(host, "[]"), fmt.Sprintf("%d", port))
+	}
+	if protocol == "ws" {
+		netAddress = "ws://" + netAddress + cmd.String("path")
+	}
+
+	if cmd.Bool("ipv4") && cmd.Bool("ipv6") {
+		return "", "", fmt.Errorf("--ipv4 and --ipv6 cannot be used together")
+	}
+	if cmd.Bool("ipv4") || cmd.Bool("ipv6") {
+		// NOTE: stdin packets are forwarded over UDP so the suffix carries over to that
+		if protocol != "udp" && protocol != "tcp" && protocol != "stdin" {
+			return "", "", fmt.Errorf("--ipv4 and --ipv6 are only supported for the udp and tcp protocols")
+		}
+		if cmd.Bool("ipv4") {
+			protocol = protocol + "4"
+		} else {
+			protocol = protocol + "6"
+		}
+	}
+	return netAddress, protocol, nil
+}
+
+// send writes count messages to conn, one every interval, count 0 sends until ctx is done
+func send(ctx context.Context, conn osc.PacketWriter, address string, args []string, types []string, slip bool, count int, interval time.Duration) (int, error) {
+
+	staticArgs := []osc.OSCArg{}
+	generators := []generator{}
+	argTypes := []string{}
+
+	for index, arg := range args {
+		oscType := "s"
+		if len(types) > index {
+			oscType = types[index]
+		}
+
+		var argGenerator generator
+		switch oscType {
+		case "i", "h", "f", "d":
+			var err error
+			argGenerator, _, err = parseGenerator(arg)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		if argGenerator != nil {
+			staticArgs = append(staticArgs, osc.OSCArg{})
+		} else {
+			staticArg, err := argToTypedArg(arg, oscType)
+			if err != nil {
+				return 0, err
+			}
+			staticArgs = append(staticArgs, staticArg)
+		}
+		generators = append(generators, argGenerator)
+		argTypes = append(argTypes, oscType)
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	sent := 0
+	for index := 0; count == 0 || index < count; index++ {
+		// NOTE: pace against the start time so the interval doesn't drift with send latency
+		offset := time.Duration(index) * interval
+		if index > 0 && interval > 0 {
+			timer.Reset(time.Until(start.Add(offset)))
+			select {
+			case <-ctx.Done():
+				return sent, nil
+			case <-timer.C:
+			}
+		} else if ctx.Err() != nil {
+			return sent, nil
+		}
+
+		oscMessage := osc.OSCMessage{
+			Address: address,
+			Args:    []osc.OSCArg{},
+		}
+		for argIndex, arg := range staticArgs {
+			if generators[argIndex] != nil {
+				var err error
+				arg, err = generatedArg(generators[argIndex](offset, index), argTypes[argIndex])
+				if err != nil {
+					return sent, err
+				}
+			}
+			oscMessage.Args = append(oscMessage.Args, arg)
+		}
+
+		if err := writePacket(conn, &oscMessage, slip); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// forward sends every packet read from source to conn until source is drained
+func forward(source osc.Conn, conn osc.Conn, slip bool) error {
+	for {
+		packet, _, err := source.ReadPacket()
+		if err != nil {
+			var decodeErr *osc.DecodeError
+			if errors.As(err, &decodeErr) {
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		if err := writePacket(conn, packet, slip); err != nil {
+			return err
+		}
+	}
+}
+
+func dial(netAddress string, protocol string, slip bool, udpConfig osc.UDPConfig, tlsConfig *tls.Config, jsonFrames bool, baud int) (osc.Conn, error) {
+	switch protocol {
+	case "udp", "udp4", "udp6":
+		return udpConfig.Dial(protocol, netAddress)
+	case "unixgram":
+		return osc.DialUnixgram("", netAddress)
+	case "ws":
+		origin := "http://" + strings.TrimPrefix(netAddress, "ws://")
+		return osc.DialWebSocket(netAddress, origin, jsonFrames)
+	case "serial":
+		return osc.OpenSerial(netAddress, baud)
+	default:
+		framing := osc.SizeFraming
+		if slip {
+			framing = osc.SLIPFraming
+		}
+		if tlsConfig != nil {
+			return osc.DialTLS(protocol, netAddress, framing, tlsConfig)
+		}
+		return osc.DialStream(protocol, netAddress, framing)
+	}
+}
+
+func writePacket(conn osc.PacketWriter, packet osc.OSCPacket, slip bool) error {
+	switch conn.(type) {
+	case *osc.UDPConn, *osc.UnixgramConn:
+		if slip {
+			// NOTE: datagrams don't need framing but some devices expect SLIP anyway
+			packetBytes, err := packet.ToBytes()
+			if err != nil {
+				return err
+			}
+			_, err = conn.(io.Writer).Write(osc.SLIPEncode(packetBytes))
+			return err
+		}
+	}
+	return conn.WritePacket(packet)
+}
+
+type packetWriterFunc func(packet osc.OSCPacket) error
+
+func (f packetWriterFunc) WritePacket(packet osc.OSCPacket) error {
+	return f(packet)
+}
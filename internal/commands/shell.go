@@ -0,0 +1,448 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+	"github.com/jwetzell/osc-go/internal/output"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+var shellProtocols = []string{"udp", "tcp", "unix", "unixgram", "ws", "serial"}
+
+var shellCommands = []string{":bundle", ":timetag", ":connect", ":help", ":quit"}
+
+const shellHelp = `messages use the text syntax (/address ,types args) or packet JSON, the type tag can be left off
+:connect PROTOCOL ADDRESS  connect to udp|tcp|unix|unixgram|ws|serial, e.g. :connect udp 127.0.0.1:8000
+                           ws needs a full URL, e.g. :connect ws ws://127.0.0.1:8080/
+:bundle                    collect the following messages into a bundle
+:bundle send               send the collected bundle
+:bundle cancel             discard the collected bundle
+:timetag [TIME]            show or set the bundle time tag: immediate, RFC3339 or +DURATION from send
+:quit                      leave the shell
+`
+
+func Shell() *cli.Command {
+	format := formatFlag("format for incoming packets to be output in (%s)").(*cli.StringFlag)
+	format.Value = "text"
+
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "host",
+			Usage: "host to connect to, or use :connect in the shell",
+		},
+		&cli.Int32Flag{
+			Name:  "port",
+			Usage: "port to connect to",
+		},
+		protocolFlag(shellProtocols, "protocol to connect with (%s)"),
+	}
+	flags = append(flags, localTransportFlags("exchange")...)
+	flags = append(flags, ipFamilyFlags("connect")...)
+	flags = append(flags,
+		format,
+		&cli.StringFlag{
+			Name:  "path",
+			Value: "/",
+			Usage: "URL path of the WebSocket endpoint (ws protocol)",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Value: false,
+			Usage: "whether to send packet JSON in text frames instead of OSC bytes in binary frames (ws protocol)",
+		},
+		slipFlag("whether to slip encode packets instead of size prefixing them on stream protocols"),
+	)
+	flags = append(flags, tlsFlags(
+		"PEM client certificate file for servers that require one (--tls)",
+		"PEM CA file to verify the server with instead of the system roots (--tls)",
+	)...)
+	flags = append(flags, &cli.BoolFlag{
+		Name:  "insecure-skip-verify",
+		Value: false,
+		Usage: "whether to skip verifying the server certificate, only for testing (--tls)",
+	})
+
+	return &cli.Command{
+		Name:  "shell",
+		Usage: "interactive session to send OSC messages and see what comes back",
+		Flags: flags,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			var tlsConfig *tls.Config
+			if cmd.Bool("tls") {
+				var err error
+				tlsConfig, err = clientTLSConfig(strings.Trim(cmd.String("host"), "[]"), cmd.String("cert"), cmd.String("key"), cmd.String("ca"), cmd.Bool("insecure-skip-verify"))
+				if err != nil {
+					return err
+				}
+			}
+
+			session := &shellSession{
+				format:    cmd.String("format"),
+				slip:      cmd.Bool("slip"),
+				addresses: map[string]struct{}{},
+				timeTag:   "immediate",
+				dial: func(protocol string, address string) (osc.Conn, error) {
+					if tlsConfig != nil && !strings.HasPrefix(protocol, "tcp") {
+						return nil, fmt.Errorf("--tls is only supported for the tcp protocol")
+					}
+					return dial(address, protocol, cmd.Bool("slip"), osc.UDPConfig{}, tlsConfig, cmd.Bool("json"), cmd.Int("baud"))
+				},
+			}
+
+			var reader shellReader
+			if term.IsTerminal(int(os.Stdin.Fd())) {
+				state, err := term.MakeRaw(int(os.Stdin.Fd()))
+				if err != nil {
+					return err
+				}
+				defer term.Restore(int(os.Stdin.Fd()), state)
+
+				terminal := term.NewTerminal(struct {
+					io.Reader
+					io.Writer
+				}{os.Stdin, os.Stdout}, "osc> ")
+				if width, height, err := term.GetSize(int(os.Stdin.Fd())); err == nil && width > 0 {
+					terminal.SetSize(width, height)
+				}
+				terminal.AutoCompleteCallback = session.complete
+				session.writer = terminal
+				session.setPrompt = terminal.SetPrompt
+				reader = terminal
+			} else {
+				session.writer = os.Stdout
+				reader = &lineReader{scanner: bufio.NewScanner(os.Stdin)}
+			}
+
+			if cmd.IsSet("host") || cmd.IsSet("socket") || cmd.IsSet("device") {
+				netAddress, protocol, err := target(cmd)
+				if err != nil {
+					return err
+				}
+				if err := session.connect(protocol, netAddress); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintln(session.writer, "not connected, use :connect PROTOCOL ADDRESS or :help")
+			}
+			defer session.close()
+
+			return session.run(reader)
+		},
+	}
+}
+
+type shellReader interface {
+	ReadLine() (string, error)
+}
+
+// lineReader reads the shell input when stdin is not a terminal, like a piped script
+type lineReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *lineReader) ReadLine() (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+type shellSession struct {
+	mutex     sync.Mutex
+	conn      osc.Conn
+	format    string
+	slip      bool
+	writer    io.Writer
+	setPrompt func(prompt string)
+	dial      func(protocol string, address string) (osc.Conn, error)
+	addresses map[string]struct{}
+	timeTag   string
+	bundle    *osc.OSCBundle
+}
+
+func (s *shellSession) run(reader shellReader) error {
+	for {
+		line, err := reader.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if line == ":quit" || line == ":q" {
+			return nil
+		}
+
+		if err := s.handleLine(line); err != nil {
+			fmt.Fprintf(s.writer, "error: %s\n", err)
+		}
+	}
+}
+
+func (s *shellSession) handleLine(line string) error {
+	if strings.HasPrefix(line, ":") {
+		return s.handleCommand(strings.Fields(line))
+	}
+
+	packet, err := packetFromLine(line)
+	if err != nil {
+		return err
+	}
+	s.remember(packet)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.bundle != nil {
+		s.bundle.Contents = append(s.bundle.Contents, packet)
+		s.updatePrompt()
+		return nil
+	}
+	return s.send(packet)
+}
+
+func (s *shellSession) handleCommand(fields []string) error {
+	switch fields[0] {
+	case ":help":
+		fmt.Fprint(s.writer, shellHelp)
+	case ":connect":
+		if len(fields) != 3 {
+			return errors.New("usage: :connect PROTOCOL ADDRESS")
+		}
+		return s.connect(fields[1], fields[2])
+	case ":timetag":
+		if len(fields) == 1 {
+			s.mutex.Lock()
+			fmt.Fprintf(s.writer, "time tag: %s\n", s.timeTag)
+			s.mutex.Unlock()
+			return nil
+		}
+		if _, err := resolveTimeTag(fields[1]); err != nil {
+			return err
+		}
+		s.mutex.Lock()
+		s.timeTag = fields[1]
+		s.mutex.Unlock()
+	case ":bundle":
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		action := ""
+		if len(fields) > 1 {
+			action = fields[1]
+		}
+		switch action {
+		case "":
+			if s.bundle == nil {
+				s.bundle = &osc.OSCBundle{Contents: []osc.OSCPacket{}}
+			}
+		case "send":
+			if s.bundle == nil {
+				return errors.New("no bundle started, use :bundle first")
+			}
+			timeTag, err := resolveTimeTag(s.timeTag)
+			if err != nil {
+				return err
+			}
+			s.bundle.TimeTag = timeTag
+			if err := s.send(s.bundle); err != nil {
+				return err
+			}
+			s.bundle = nil
+		case "cancel":
+			s.bundle = nil
+		default:
+			return errors.New("usage: :bundle [send|cancel]")
+		}
+		s.updatePrompt()
+	default:
+		return fmt.Errorf("unknown command %s, try :help", fields[0])
+	}
+	return nil
+}
+
+// send writes packet to the current connection, s.mutex must be held
+func (s *shellSession) send(packet osc.OSCPacket) error {
+	if s.conn == nil {
+		return errors.New("not connected, use :connect PROTOCOL ADDRESS")
+	}
+	return writePacket(s.conn, packet, s.slip)
+}
+
+func (s *shellSession) updatePrompt() {
+	if s.setPrompt == nil {
+		return
+	}
+	if s.bundle != nil {
+		s.setPrompt(fmt.Sprintf("osc bundle[%d]> ", len(s.bundle.Contents)))
+		return
+	}
+	s.setPrompt("osc> ")
+}
+
+func (s *shellSession) connect(protocol string, address string) error {
+	// NOTE: only udp and tcp take a 4 or 6 suffix to pick the IP family
+	family := strings.TrimRight(protocol, "46")
+	if !slices.Contains(shellProtocols, family) || (family != protocol && family != "udp" && family != "tcp") {
+		return fmt.Errorf("protocol must be one of %s", strings.Join(shellProtocols, ", "))
+	}
+	if family == "ws" && !strings.HasPrefix(address, "ws://") {
+		return fmt.Errorf("ws needs a full URL like ws://127.0.0.1:8080/")
+	}
+
+	conn, err := s.dial(protocol, address)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	previous := s.conn
+	s.conn = conn
+	s.mutex.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+	fmt.Fprintf(s.writer, "connected to %s %s\n", protocol, address)
+
+	go s.receive(conn, protocol)
+	return nil
+}
+
+func (s *shellSession) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// receive prints everything that arrives on conn until it is closed
+func (s *shellSession) receive(conn osc.Conn, protocol string) {
+	out := output.New(s.writer, s.format, false, nil, "inline")
+	for {
+		packet, source, err := conn.ReadPacket()
+		if err != nil {
+			var decodeErr *osc.DecodeError
+			if errors.As(err, &decodeErr) {
+				out.WriteError(decodeErr.Bytes, decodeErr.Err, output.Source{Protocol: protocol, Address: source, Received: time.Now()})
+				continue
+			}
+
+			s.mutex.Lock()
+			current := s.conn == conn
+			s.mutex.Unlock()
+			// NOTE: a replaced connection was closed on purpose
+			if current && !errors.Is(err, net.ErrClosed) {
+				fmt.Fprintf(s.writer, "connection lost: %s\n", err)
+			}
+			return
+		}
+
+		s.remember(packet)
+		out.WritePacket(packet, output.Source{Protocol: protocol, Address: source, Received: time.Now()})
+	}
+}
+
+// remember records the addresses in packet for tab completion
+func (s *shellSession) remember(packet osc.OSCPacket) {
+	switch packet := packet.(type) {
+	case *osc.OSCMessage:
+		s.mutex.Lock()
+		s.addresses[packet.Address] = struct{}{}
+		s.mutex.Unlock()
+	case *osc.OSCBundle:
+		for _, content := range packet.Contents {
+			s.remember(content)
+		}
+	}
+}
+
+// complete is the terminal AutoCompleteCallback, tab completes the address or meta-command at the start of the line
+func (s *shellSession) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	word := line[:pos]
+	if strings.ContainsAny(word, " \t") {
+		return "", 0, false
+	}
+
+	candidates := []string{}
+	if strings.HasPrefix(word, ":") {
+		candidates = shellCommands
+	} else {
+		s.mutex.Lock()
+		for address := range s.addresses {
+			candidates = append(candidates, address)
+		}
+		s.mutex.Unlock()
+	}
+
+	matches := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+	sort.Strings(matches)
+
+	completion := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, completion) {
+			completion = completion[:len(completion)-1]
+		}
+	}
+
+	if completion == word && len(matches) > 1 {
+		fmt.Fprintln(s.writer, strings.Join(matches, "  "))
+		return "", 0, false
+	}
+	return completion + line[pos:], len(completion), true
+}
+
+// resolveTimeTag turns immediate, an RFC3339 time or +DURATION into a time tag
+func resolveTimeTag(spec string) (osc.OSCTimeTag, error) {
+	if spec == "immediate" {
+		return osc.ImmediateTimeTag(), nil
+	}
+	if strings.HasPrefix(spec, "+") {
+		offset, err := time.ParseDuration(spec[1:])
+		if err != nil {
+			return osc.OSCTimeTag{}, fmt.Errorf("time tag must be immediate, RFC3339 or +DURATION but got %s", spec)
+		}
+		return osc.TimeTagFromTime(time.Now().Add(offset)), nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, spec)
+	if err != nil {
+		return osc.OSCTimeTag{}, fmt.Errorf("time tag must be immediate, RFC3339 or +DURATION but got %s", spec)
+	}
+	return osc.TimeTagFromTime(parsed), nil
+}
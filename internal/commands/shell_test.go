@@ -0,0 +1,338 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+// scriptReader is a shellReader that hands out lines then err, io.EOF if err is nil
+type scriptReader struct {
+	lines []string
+	err   error
+}
+
+func (r *scriptReader) ReadLine() (string, error) {
+	if len(r.lines) == 0 {
+		if r.err != nil {
+			return "", r.err
+		}
+		return "", io.EOF
+	}
+	line := r.lines[0]
+	r.lines = r.lines[1:]
+	return line, nil
+}
+
+// shellConn is an osc.Conn that records written packets and never receives any
+type shellConn struct {
+	packets []osc.OSCPacket
+	closed  chan struct{}
+}
+
+func newShellConn() *shellConn {
+	return &shellConn{packets: []osc.OSCPacket{}, closed: make(chan struct{})}
+}
+
+func (c *shellConn) ReadPacket() (osc.OSCPacket, net.Addr, error) {
+	<-c.closed
+	return nil, nil, net.ErrClosed
+}
+
+func (c *shellConn) WritePacket(packet osc.OSCPacket) error {
+	c.packets = append(c.packets, packet)
+	return nil
+}
+
+func (c *shellConn) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func newTestShellSession(conn *shellConn, writer io.Writer) *shellSession {
+	return &shellSession{
+		format:    "text",
+		writer:    writer,
+		addresses: map[string]struct{}{},
+		timeTag:   "immediate",
+		dial: func(protocol string, address string) (osc.Conn, error) {
+			return conn, nil
+		},
+	}
+}
+
+func TestShellSession(t *testing.T) {
+	cue := &osc.OSCMessage{Address: "/cue/go", Args: []osc.OSCArg{{Type: "i", Value: int32(12)}}}
+	fader := &osc.OSCMessage{Address: "/ch/1/mix/fader", Args: []osc.OSCArg{{Type: "f", Value: float32(0.5)}}}
+	connected := "connected to udp 127.0.0.1:8000\n"
+
+	testCases := []struct {
+		name     string
+		lines    []string
+		sent     []osc.OSCPacket
+		output   string
+		prompts  []string
+		readErr  error
+		errorMsg string
+	}{
+		{
+			name:   "message",
+			lines:  []string{":connect udp 127.0.0.1:8000", "/cue/go ,i 12"},
+			sent:   []osc.OSCPacket{cue},
+			output: connected,
+		},
+		{
+			name:   "blank lines are skipped",
+			lines:  []string{":connect udp 127.0.0.1:8000", "", "   ", "/cue/go ,i 12"},
+			sent:   []osc.OSCPacket{cue},
+			output: connected,
+		},
+		{
+			name:   "not connected",
+			lines:  []string{"/cue/go ,i 12"},
+			sent:   []osc.OSCPacket{},
+			output: "error: not connected, use :connect PROTOCOL ADDRESS\n",
+		},
+		{
+			name:   "bad line",
+			lines:  []string{":connect udp 127.0.0.1:8000", "cue/go"},
+			sent:   []osc.OSCPacket{},
+			output: connected + "error: OSC packet text must start with / for a message or #bundle for a bundle\n",
+		},
+		{
+			name:  "bundle",
+			lines: []string{":connect udp 127.0.0.1:8000", ":bundle", "/cue/go ,i 12", "/ch/1/mix/fader ,f 0.5", ":bundle send"},
+			sent: []osc.OSCPacket{
+				&osc.OSCBundle{TimeTag: osc.ImmediateTimeTag(), Contents: []osc.OSCPacket{cue, fader}},
+			},
+			output:  connected,
+			prompts: []string{"osc bundle[0]> ", "osc bundle[1]> ", "osc bundle[2]> ", "osc> "},
+		},
+		{
+			name:    "bundle cancel",
+			lines:   []string{":connect udp 127.0.0.1:8000", ":bundle", "/cue/go ,i 12", ":bundle cancel", "/ch/1/mix/fader ,f 0.5"},
+			sent:    []osc.OSCPacket{fader},
+			output:  connected,
+			prompts: []string{"osc bundle[0]> ", "osc bundle[1]> ", "osc> "},
+		},
+		{
+			name:   "bundle send without a bundle",
+			lines:  []string{":connect udp 127.0.0.1:8000", ":bundle send"},
+			sent:   []osc.OSCPacket{},
+			output: connected + "error: no bundle started, use :bundle first\n",
+		},
+		{
+			name:   "bad bundle action",
+			lines:  []string{":bundle flush"},
+			sent:   []osc.OSCPacket{},
+			output: "error: usage: :bundle [send|cancel]\n",
+		},
+		{
+			name:   "bundle send not connected keeps the bundle",
+			lines:  []string{":bundle", "/cue/go ,i 12", ":bundle send", ":connect udp 127.0.0.1:8000", ":bundle send"},
+			sent:   []osc.OSCPacket{&osc.OSCBundle{TimeTag: osc.ImmediateTimeTag(), Contents: []osc.OSCPacket{cue}}},
+			output: "error: not connected, use :connect PROTOCOL ADDRESS\n" + connected,
+		},
+		{
+			name:   "timetag",
+			lines:  []string{":timetag", ":timetag 2024-01-01T00:00:00Z", ":timetag"},
+			sent:   []osc.OSCPacket{},
+			output: "time tag: immediate\ntime tag: 2024-01-01T00:00:00Z\n",
+		},
+		{
+			name:  "bundle with a timetag",
+			lines: []string{":connect udp 127.0.0.1:8000", ":timetag 2024-01-01T00:00:00Z", ":bundle", "/cue/go ,i 12", ":bundle send"},
+			sent: []osc.OSCPacket{
+				&osc.OSCBundle{TimeTag: osc.NewOSCTimeTag(3913056000, 0), Contents: []osc.OSCPacket{cue}},
+			},
+			output: connected,
+		},
+		{
+			name:   "bad timetag",
+			lines:  []string{":timetag tomorrow", ":timetag"},
+			sent:   []osc.OSCPacket{},
+			output: "error: time tag must be immediate, RFC3339 or +DURATION but got tomorrow\ntime tag: immediate\n",
+		},
+		{
+			name:   "connect usage",
+			lines:  []string{":connect udp"},
+			sent:   []osc.OSCPacket{},
+			output: "error: usage: :connect PROTOCOL ADDRESS\n",
+		},
+		{
+			name:   "connect bad protocol",
+			lines:  []string{":connect ws4 ws://127.0.0.1:8080/", ":connect ws 127.0.0.1:8080"},
+			sent:   []osc.OSCPacket{},
+			output: "error: protocol must be one of udp, tcp, unix, unixgram, ws, serial\nerror: ws needs a full URL like ws://127.0.0.1:8080/\n",
+		},
+		{
+			name:   "unknown command",
+			lines:  []string{":send"},
+			sent:   []osc.OSCPacket{},
+			output: "error: unknown command :send, try :help\n",
+		},
+		{
+			name:   "help",
+			lines:  []string{":help"},
+			sent:   []osc.OSCPacket{},
+			output: shellHelp,
+		},
+		{
+			name:   "quit",
+			lines:  []string{":connect udp 127.0.0.1:8000", ":quit", "/cue/go ,i 12"},
+			sent:   []osc.OSCPacket{},
+			output: connected,
+		},
+		{
+			name:     "read error",
+			lines:    []string{},
+			sent:     []osc.OSCPacket{},
+			readErr:  errors.New("terminal closed"),
+			errorMsg: "terminal closed",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			conn := newShellConn()
+			var output bytes.Buffer
+			session := newTestShellSession(conn, &output)
+			prompts := []string{}
+			session.setPrompt = func(prompt string) {
+				prompts = append(prompts, prompt)
+			}
+
+			err := session.run(&scriptReader{lines: testCase.lines, err: testCase.readErr})
+			session.close()
+
+			if testCase.errorMsg != "" {
+				if err == nil {
+					t.Fatalf("expected shell to fail")
+				}
+				if err.Error() != testCase.errorMsg {
+					t.Fatalf("failed to run shell got error '%s', expected '%s'", err.Error(), testCase.errorMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to run shell: %s", err.Error())
+			}
+			if !reflect.DeepEqual(conn.packets, testCase.sent) {
+				t.Fatalf("failed to send packets got '%v', expected '%v'", conn.packets, testCase.sent)
+			}
+			if output.String() != testCase.output {
+				t.Fatalf("failed to write output got '%s', expected '%s'", output.String(), testCase.output)
+			}
+			if testCase.prompts != nil && !reflect.DeepEqual(prompts, testCase.prompts) {
+				t.Fatalf("failed to update prompt got '%v', expected '%v'", prompts, testCase.prompts)
+			}
+		})
+	}
+}
+
+func TestShellComplete(t *testing.T) {
+	testCases := []struct {
+		name      string
+		line      string
+		pos       int
+		key       rune
+		newLine   string
+		newPos    int
+		ok        bool
+		output    string
+		addresses []string
+	}{
+		{name: "command", line: ":bu", pos: 3, key: '\t', newLine: ":bundle", newPos: 7, ok: true},
+		{name: "command keeps the rest of the line", line: ":co udp", pos: 3, key: '\t', newLine: ":connect udp", newPos: 8, ok: true},
+		{name: "unknown command", line: ":x", pos: 2, key: '\t', ok: false},
+		{name: "not tab", line: ":bu", pos: 3, key: 'a', ok: false},
+		{name: "past the first word", line: "/cue/go ,i", pos: 10, key: '\t', addresses: []string{"/cue/go"}, ok: false},
+		{name: "address", line: "/cu", pos: 3, key: '\t', addresses: []string{"/cue/go", "/ch/1/mix/fader"}, newLine: "/cue/go", newPos: 7, ok: true},
+		{name: "common prefix", line: "/ch", pos: 3, key: '\t', addresses: []string{"/ch/1/mix/fader", "/ch/1/mix/on", "/cue/go"}, newLine: "/ch/1/mix/", newPos: 10, ok: true},
+		{
+			name:      "ambiguous lists the matches",
+			line:      "/ch/1/mix/",
+			pos:       10,
+			key:       '\t',
+			addresses: []string{"/ch/1/mix/on", "/ch/1/mix/fader"},
+			ok:        false,
+			output:    "/ch/1/mix/fader  /ch/1/mix/on\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var output bytes.Buffer
+			session := newTestShellSession(nil, &output)
+			for _, address := range testCase.addresses {
+				session.remember(&osc.OSCMessage{Address: address, Args: []osc.OSCArg{}})
+			}
+
+			newLine, newPos, ok := session.complete(testCase.line, testCase.pos, testCase.key)
+			if ok != testCase.ok || newLine != testCase.newLine || newPos != testCase.newPos {
+				t.Fatalf("failed to complete '%s' got '%s' %d %t, expected '%s' %d %t", testCase.line, newLine, newPos, ok, testCase.newLine, testCase.newPos, testCase.ok)
+			}
+			if output.String() != testCase.output {
+				t.Fatalf("failed to list matches got '%s', expected '%s'", output.String(), testCase.output)
+			}
+		})
+	}
+}
+
+func TestResolveTimeTag(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     string
+		expected osc.OSCTimeTag
+	}{
+		{name: "immediate", spec: "immediate", expected: osc.ImmediateTimeTag()},
+		{name: "RFC3339", spec: "2024-01-01T00:00:00Z", expected: osc.NewOSCTimeTag(3913056000, 0)},
+		{name: "RFC3339 with fractional seconds", spec: "2024-01-01T00:00:00.5Z", expected: osc.NewOSCTimeTag(3913056000, 1<<31)},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := resolveTimeTag(testCase.spec)
+			if err != nil {
+				t.Fatalf("failed to resolve time tag: %s", err.Error())
+			}
+			if got != testCase.expected {
+				t.Fatalf("failed to resolve time tag got '%v', expected '%v'", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestResolveTimeTagDuration(t *testing.T) {
+	before := time.Now()
+	got, err := resolveTimeTag("+1m30s")
+	if err != nil {
+		t.Fatalf("failed to resolve time tag: %s", err.Error())
+	}
+	after := time.Now()
+
+	resolved := got.Time()
+	// NOTE: time tags round to a few nanoseconds
+	if resolved.Before(before.Add(90*time.Second-time.Microsecond)) || resolved.After(after.Add(90*time.Second+time.Microsecond)) {
+		t.Fatalf("failed to resolve time tag got %s, expected between %s and %s", resolved, before.Add(90*time.Second), after.Add(90*time.Second))
+	}
+}
+
+func TestBadResolveTimeTag(t *testing.T) {
+	for _, spec := range []string{"tomorrow", "+soon", "+", "2024-01-01"} {
+		_, err := resolveTimeTag(spec)
+		if err == nil {
+			t.Fatalf("expected time tag '%s' to fail", spec)
+		}
+		expected := "time tag must be immediate, RFC3339 or +DURATION but got " + spec
+		if err.Error() != expected {
+			t.Fatalf("failed to reject time tag got '%s', expected '%s'", err.Error(), expected)
+		}
+	}
+}
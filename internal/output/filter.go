@@ -0,0 +1,97 @@
+package output
+
+import (
+	"regexp"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+// Filter decides which messages are written by address
+type Filter struct {
+	include []func(string) bool
+	exclude []func(string) bool
+}
+
+// NewFilter matches addresses with OSC address patterns or, if useRegex is set, regular expressions
+func NewFilter(include []string, exclude []string, useRegex bool) (*Filter, error) {
+	includeMatchers, err := compileMatchers(include, useRegex)
+	if err != nil {
+		return nil, err
+	}
+	excludeMatchers, err := compileMatchers(exclude, useRegex)
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{
+		include: includeMatchers,
+		exclude: excludeMatchers,
+	}, nil
+}
+
+func compileMatchers(patterns []string, useRegex bool) ([]func(string) bool, error) {
+	matchers := []func(string) bool{}
+	for _, pattern := range patterns {
+		if useRegex {
+			regex, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, regex.MatchString)
+		} else {
+			addressPattern, err := osc.CompileAddressPattern(pattern)
+			if err != nil {
+				return nil, err
+			}
+			matchers = append(matchers, addressPattern.Match)
+		}
+	}
+	return matchers, nil
+}
+
+// Allows reports whether a message with address passes the filter
+func (f *Filter) Allows(address string) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, match := range f.exclude {
+		if match(address) {
+			return false
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+
+	for _, match := range f.include {
+		if match(address) {
+			return true
+		}
+	}
+	return false
+}
+
+// Prune returns the packet with any filtered messages removed or nil if nothing is left
+func (f *Filter) Prune(packet osc.OSCPacket) osc.OSCPacket {
+	switch packet := packet.(type) {
+	case *osc.OSCMessage:
+		if f.Allows(packet.Address) {
+			return packet
+		}
+	case *osc.OSCBundle:
+		contents := []osc.OSCPacket{}
+		for _, content := range packet.Contents {
+			if pruned := f.Prune(content); pruned != nil {
+				contents = append(contents, pruned)
+			}
+		}
+		if len(contents) > 0 {
+			return &osc.OSCBundle{
+				TimeTag:  packet.TimeTag,
+				Contents: contents,
+			}
+		}
+	}
+	return nil
+}
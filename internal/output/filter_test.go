@@ -0,0 +1,154 @@
+package output
+
+import (
+	"reflect"
+	"testing"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+func TestFilterAllows(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		include []string
+		exclude []string
+		regex   bool
+		allowed map[string]bool
+	}{
+		{
+			name:    "no patterns",
+			allowed: map[string]bool{"/ch/1/mix/fader": true, "/meter/1": true},
+		},
+		{
+			name:    "include",
+			include: []string{"/ch/*/mix/fader"},
+			allowed: map[string]bool{"/ch/1/mix/fader": true, "/ch/1/mix/on": false, "/meter/1": false},
+		},
+		{
+			name:    "exclude",
+			exclude: []string{"/meter/*"},
+			allowed: map[string]bool{"/ch/1/mix/fader": true, "/meter/1": false},
+		},
+		{
+			name:    "exclude wins over include",
+			include: []string{"/ch/*/mix/*"},
+			exclude: []string{"/ch/*/mix/on"},
+			allowed: map[string]bool{"/ch/1/mix/fader": true, "/ch/1/mix/on": false},
+		},
+		{
+			name:    "regex",
+			include: []string{`^/ch/\d+/`},
+			regex:   true,
+			allowed: map[string]bool{"/ch/12/mix/fader": true, "/ch/main/mix/fader": false},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filter, err := NewFilter(testCase.include, testCase.exclude, testCase.regex)
+			if err != nil {
+				t.Fatalf("failed to create filter: %s", err.Error())
+			}
+			for address, allowed := range testCase.allowed {
+				if filter.Allows(address) != allowed {
+					t.Fatalf("failed to filter %s got %t, expected %t", address, !allowed, allowed)
+				}
+			}
+		})
+	}
+}
+
+func TestNilFilterAllows(t *testing.T) {
+	var filter *Filter
+	if !filter.Allows("/anything") {
+		t.Fatalf("expected a nil filter to allow everything")
+	}
+}
+
+func TestBadNewFilter(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		regex    bool
+		errorMsg string
+	}{
+		{
+			name:     "bad include pattern",
+			include:  []string{"ch/*"},
+			errorMsg: "OSC address pattern must start with /",
+		},
+		{
+			name:     "bad exclude regex",
+			exclude:  []string{"/ch/("},
+			regex:    true,
+			errorMsg: "error parsing regexp: missing closing ): `/ch/(`",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewFilter(testCase.include, testCase.exclude, testCase.regex)
+			if err == nil {
+				t.Fatalf("expected filter to fail")
+			}
+			if err.Error() != testCase.errorMsg {
+				t.Fatalf("failed to reject filter got '%s', expected '%s'", err.Error(), testCase.errorMsg)
+			}
+		})
+	}
+}
+
+func TestFilterPrune(t *testing.T) {
+	fader := &osc.OSCMessage{Address: "/ch/1/mix/fader", Args: []osc.OSCArg{}}
+	meter := &osc.OSCMessage{Address: "/meter/1", Args: []osc.OSCArg{}}
+	timeTag := osc.NewOSCTimeTag(3913056000, 0)
+
+	testCases := []struct {
+		name     string
+		packet   osc.OSCPacket
+		expected osc.OSCPacket
+	}{
+		{
+			name:     "allowed message",
+			packet:   fader,
+			expected: fader,
+		},
+		{
+			name:     "filtered message",
+			packet:   meter,
+			expected: nil,
+		},
+		{
+			name: "nested bundle",
+			packet: &osc.OSCBundle{TimeTag: timeTag, Contents: []osc.OSCPacket{
+				meter,
+				&osc.OSCBundle{TimeTag: timeTag, Contents: []osc.OSCPacket{fader, meter}},
+			}},
+			expected: &osc.OSCBundle{TimeTag: timeTag, Contents: []osc.OSCPacket{
+				&osc.OSCBundle{TimeTag: timeTag, Contents: []osc.OSCPacket{fader}},
+			}},
+		},
+		{
+			name:     "bundle with nothing left",
+			packet:   &osc.OSCBundle{TimeTag: timeTag, Contents: []osc.OSCPacket{meter, meter}},
+			expected: nil,
+		},
+	}
+
+	filter, err := NewFilter([]string{}, []string{"/meter/*"}, false)
+	if err != nil {
+		t.Fatalf("failed to create filter: %s", err.Error())
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := filter.Prune(testCase.packet)
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to prune got '%v', expected '%v'", got, testCase.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+// Package output formats received OSC packets for the command line tools
+package output
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+// Formats are the supported output formats
+var Formats = []string{"json", "ndjson", "text", "csv", "hex"}
+
+// ErrorModes are the ways malformed packets can be reported
+var ErrorModes = []string{"stderr", "inline", "ignore"}
+
+// Source describes where and when a packet was received
+type Source struct {
+	Protocol string
+	Address  net.Addr
+	Received time.Time
+}
+
+type record struct {
+	source     Source
+	bundlePath []int
+	timeTag    *osc.OSCTimeTag
+	packet     osc.OSCPacket
+}
+
+type envelope struct {
+	Time       string          `json:"time"`
+	Source     string          `json:"source,omitempty"`
+	Protocol   string          `json:"protocol"`
+	BundlePath []int           `json:"bundlePath,omitempty"`
+	TimeTag    *osc.OSCTimeTag `json:"timeTag,omitempty"`
+	Packet     osc.OSCPacket   `json:"packet"`
+}
+
+type errorRecord struct {
+	Time       string `json:"time"`
+	Source     string `json:"source,omitempty"`
+	Protocol   string `json:"protocol"`
+	Error      string `json:"error"`
+	Hex        string `json:"hex"`
+	ErrorCount uint64 `json:"errorCount"`
+}
+
+// Output writes packets and errors in one of the Formats
+type Output struct {
+	format        string
+	keepBundles   bool
+	filter        *Filter
+	errorMode     string
+	writer        io.Writer
+	packetWriter  osc.PacketWriter
+	errWriter     io.Writer
+	csvWriter     *csv.Writer
+	headerWritten bool
+	packetCount   uint64
+	errorCount    uint64
+	mutex         sync.Mutex
+}
+
+// New creates an Output, filter may be nil to write every message
+func New(writer io.Writer, format string, keepBundles bool, filter *Filter, errorMode string) *Output {
+	return &Output{
+		format:      format,
+		keepBundles: keepBundles,
+		filter:      filter,
+		errorMode:   errorMode,
+		writer:      writer,
+		errWriter:   os.Stderr,
+		csvWriter:   csv.NewWriter(writer),
+	}
+}
+
+// NewPacketWriter creates an Output that writes packets to writer as they are instead of formatting them, bundles are
+// kept whole with filtered messages removed and filter may be nil to write every message
+func NewPacketWriter(writer osc.PacketWriter, filter *Filter, errorMode string) *Output {
+	output := New(io.Discard, "", true, filter, errorMode)
+	output.packetWriter = writer
+	return output
+}
+
+// WritePacket writes packet, flattening bundles into their messages unless keepBundles was set
+//
+// The hex format always writes the whole packet as it was received, the filter only decides whether it is written.
+func (o *Output) WritePacket(packet osc.OSCPacket, source Source) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.packetCount++
+
+	if o.format == "hex" {
+		if o.filter.Prune(packet) != nil {
+			o.writeRecord(record{source: source, packet: packet})
+		}
+		return
+	}
+	if o.keepBundles {
+		if pruned := o.filter.Prune(packet); pruned != nil {
+			o.writeRecord(record{source: source, packet: pruned})
+		}
+		return
+	}
+	o.flatten(packet, source, []int{}, nil)
+}
+
+func (o *Output) flatten(packet osc.OSCPacket, source Source, bundlePath []int, timeTag *osc.OSCTimeTag) {
+	if bundle, ok := packet.(*osc.OSCBundle); ok {
+		for index, content := range bundle.Contents {
+			contentPath := append(append([]int{}, bundlePath...), index)
+			o.flatten(content, source, contentPath, &bundle.TimeTag)
+		}
+		return
+	}
+	if message, ok := packet.(*osc.OSCMessage); ok && !o.filter.Allows(message.Address) {
+		return
+	}
+	o.writeRecord(record{source: source, bundlePath: bundlePath, timeTag: timeTag, packet: packet})
+}
+
+func (o *Output) writeRecord(r record) {
+	var err error
+	if o.packetWriter != nil {
+		err = o.packetWriter.WritePacket(r.packet)
+	}
+	switch o.format {
+	case "json":
+		err = o.writeJSON(r.packet)
+	case "ndjson":
+		err = o.writeJSON(newEnvelope(r))
+	case "text":
+		err = o.writeText(r)
+	case "csv":
+		err = o.writeCSV(r)
+	case "hex":
+		err = o.writeHex(r)
+	}
+	if err != nil {
+		fmt.Fprintln(o.errWriter, err)
+	}
+}
+
+// WriteError reports a malformed packet according to the error mode
+func (o *Output) WriteError(payload []byte, decodeErr error, source Source) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.errorCount++
+
+	record := errorRecord{
+		Time:       source.Received.Format(time.RFC3339Nano),
+		Protocol:   source.Protocol,
+		Error:      decodeErr.Error(),
+		Hex:        hex.EncodeToString(payload),
+		ErrorCount: o.errorCount,
+	}
+	if source.Address != nil {
+		record.Source = source.Address.String()
+	}
+
+	switch o.errorMode {
+	case "stderr":
+		fmt.Fprintf(o.errWriter, "%s %s malformed %s packet #%d: %s (hex: %s)\n", record.Time, record.Source, record.Protocol, record.ErrorCount, record.Error, record.Hex)
+	case "inline":
+		if err := o.writeJSON(record); err != nil {
+			fmt.Fprintln(o.errWriter, err)
+		}
+	}
+}
+
+// WriteSummary writes the packet and error counts
+func (o *Output) WriteSummary(writer io.Writer) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	fmt.Fprintf(writer, "received %d packets, %d malformed\n", o.packetCount, o.errorCount)
+}
+
+func newEnvelope(r record) envelope {
+	e := envelope{
+		Time:       r.source.Received.Format(time.RFC3339Nano),
+		Protocol:   r.source.Protocol,
+		BundlePath: r.bundlePath,
+		TimeTag:    r.timeTag,
+		Packet:     r.packet,
+	}
+	if r.source.Address != nil {
+		e.Source = r.source.Address.String()
+	}
+	return e
+}
+
+func (o *Output) writeJSON(value any) error {
+	jsonData, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(o.writer, string(jsonData))
+	return err
+}
+
+func (o *Output) writeText(r record) error {
+	source := "-"
+	if r.source.Address != nil {
+		source = r.source.Address.String()
+	}
+	_, err := fmt.Fprintf(o.writer, "%s %s %v\n", r.source.Received.Format(time.RFC3339Nano), source, r.packet)
+	return err
+}
+
+func (o *Output) writeCSV(r record) error {
+	if !o.headerWritten {
+		err := o.csvWriter.Write([]string{"time", "source", "protocol", "bundle_path", "time_tag", "address", "types", "args"})
+		if err != nil {
+			return err
+		}
+		o.headerWritten = true
+	}
+
+	bundlePath := []string{}
+	for _, index := range r.bundlePath {
+		bundlePath = append(bundlePath, strconv.Itoa(index))
+	}
+
+	timeTag := ""
+	if r.timeTag != nil {
+		timeTag = r.timeTag.String()
+	}
+
+	source := ""
+	if r.source.Address != nil {
+		source = r.source.Address.String()
+	}
+
+	fields := []string{
+		r.source.Received.Format(time.RFC3339Nano),
+		source,
+		r.source.Protocol,
+		strings.Join(bundlePath, "."),
+		timeTag,
+	}
+
+	switch packet := r.packet.(type) {
+	case *osc.OSCMessage:
+		types := ""
+		for _, arg := range packet.Args {
+			types += arg.Type
+		}
+		// NOTE: args share one column in the text syntax so every row has the same number of columns
+		args := []string{}
+		for _, arg := range packet.Args {
+			switch arg.Type {
+			case "T", "F", "N", "I":
+				continue
+			}
+			args = append(args, arg.String())
+		}
+		fields = append(fields, packet.Address, types, strings.Join(args, " "))
+	case *osc.OSCBundle:
+		fields = append(fields, "#bundle", "", packet.String())
+	}
+
+	err := o.csvWriter.Write(fields)
+	if err != nil {
+		return err
+	}
+	o.csvWriter.Flush()
+	return o.csvWriter.Error()
+}
+
+func (o *Output) writeHex(r record) error {
+	// NOTE: a decoded packet encodes back to the bytes it was decoded from
+	bytes, err := r.packet.ToBytes()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(o.writer, hex.EncodeToString(bytes))
+	return err
+}
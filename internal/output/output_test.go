@@ -0,0 +1,236 @@
+package output
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	osc "github.com/jwetzell/osc-go"
+)
+
+func TestOutputFormats(t *testing.T) {
+	source := Source{
+		Protocol: "udp",
+		Address:  &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000},
+		Received: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	fader := &osc.OSCMessage{Address: "/ch/1/mix/fader", Args: []osc.OSCArg{{Type: "f", Value: float32(0.5)}}}
+	name := &osc.OSCMessage{Address: "/ch/1/name", Args: []osc.OSCArg{{Type: "s", Value: `Lead, "Vox"`}, {Type: "T", Value: true}}}
+	meter := &osc.OSCMessage{Address: "/meter/1", Args: []osc.OSCArg{{Type: "i", Value: int32(3)}}}
+	bundle := &osc.OSCBundle{
+		TimeTag: osc.NewOSCTimeTag(3913056000, 0),
+		Contents: []osc.OSCPacket{
+			fader,
+			&osc.OSCBundle{TimeTag: osc.NewOSCTimeTag(3913056000, 0), Contents: []osc.OSCPacket{meter}},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		format      string
+		keepBundles bool
+		packets     []osc.OSCPacket
+		expected    string
+	}{
+		{
+			name:     "json",
+			format:   "json",
+			packets:  []osc.OSCPacket{fader},
+			expected: `{"address":"/ch/1/mix/fader","args":[{"value":0.5,"type":"f"}]}` + "\n",
+		},
+		{
+			name:    "ndjson flattened bundle",
+			format:  "ndjson",
+			packets: []osc.OSCPacket{bundle},
+			expected: `{"time":"2026-01-01T12:00:00Z","source":"127.0.0.1:9000","protocol":"udp","bundlePath":[0],"timeTag":{"seconds":3913056000,"fractionalSeconds":0},"packet":{"address":"/ch/1/mix/fader","args":[{"value":0.5,"type":"f"}]}}` + "\n" +
+				`{"time":"2026-01-01T12:00:00Z","source":"127.0.0.1:9000","protocol":"udp","bundlePath":[1,0],"timeTag":{"seconds":3913056000,"fractionalSeconds":0},"packet":{"address":"/meter/1","args":[{"value":3,"type":"i"}]}}` + "\n",
+		},
+		{
+			name:        "ndjson kept bundle",
+			format:      "ndjson",
+			keepBundles: true,
+			packets:     []osc.OSCPacket{bundle},
+			expected:    `{"time":"2026-01-01T12:00:00Z","source":"127.0.0.1:9000","protocol":"udp","packet":{"contents":[{"address":"/ch/1/mix/fader","args":[{"value":0.5,"type":"f"}]},{"contents":[{"address":"/meter/1","args":[{"value":3,"type":"i"}]}],"timeTag":{"seconds":3913056000,"fractionalSeconds":0}}],"timeTag":{"seconds":3913056000,"fractionalSeconds":0}}}` + "\n",
+		},
+		{
+			name:     "text",
+			format:   "text",
+			packets:  []osc.OSCPacket{fader},
+			expected: "2026-01-01T12:00:00Z 127.0.0.1:9000 /ch/1/mix/fader ,f 0.5\n",
+		},
+		{
+			name:    "csv quotes and bundle paths",
+			format:  "csv",
+			packets: []osc.OSCPacket{name, bundle},
+			expected: "time,source,protocol,bundle_path,time_tag,address,types,args\n" +
+				`2026-01-01T12:00:00Z,127.0.0.1:9000,udp,,,/ch/1/name,sT,"""Lead, \""Vox\"""""` + "\n" +
+				"2026-01-01T12:00:00Z,127.0.0.1:9000,udp,0,2024-01-01T00:00:00Z,/ch/1/mix/fader,f,0.5\n" +
+				"2026-01-01T12:00:00Z,127.0.0.1:9000,udp,1.0,2024-01-01T00:00:00Z,/meter/1,i,3\n",
+		},
+		{
+			name:        "csv kept bundle",
+			format:      "csv",
+			keepBundles: true,
+			packets:     []osc.OSCPacket{bundle},
+			expected: "time,source,protocol,bundle_path,time_tag,address,types,args\n" +
+				`2026-01-01T12:00:00Z,127.0.0.1:9000,udp,,,#bundle,,"#bundle 2024-01-01T00:00:00Z [/ch/1/mix/fader ,f 0.5; #bundle 2024-01-01T00:00:00Z [/meter/1 ,i 3]]"` + "\n",
+		},
+		{
+			name:    "hex writes whole packets",
+			format:  "hex",
+			packets: []osc.OSCPacket{fader, bundle},
+			expected: "2f63682f312f6d69782f6661646572002c6600003f000000\n" +
+				"2362756e646c6500e93c7f0000000000000000182f63682f312f6d69782f6661646572002c6600003f000000000000282362756e646c6500e93c7f0000000000000000142f6d657465722f31000000002c69000000000003\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			out := New(&buffer, testCase.format, testCase.keepBundles, nil, "stderr")
+			for _, packet := range testCase.packets {
+				out.WritePacket(packet, source)
+			}
+			if buffer.String() != testCase.expected {
+				t.Fatalf("failed to write %s got '%s', expected '%s'", testCase.format, buffer.String(), testCase.expected)
+			}
+		})
+	}
+}
+
+func TestOutputHexFilter(t *testing.T) {
+	fader := &osc.OSCMessage{Address: "/ch/1/mix/fader", Args: []osc.OSCArg{}}
+	meter := &osc.OSCMessage{Address: "/meter/1", Args: []osc.OSCArg{}}
+	bundle := &osc.OSCBundle{TimeTag: osc.ImmediateTimeTag(), Contents: []osc.OSCPacket{fader, meter}}
+	bundleBytes, err := bundle.ToBytes()
+	if err != nil {
+		t.Fatalf("failed to encode: %s", err.Error())
+	}
+
+	filter, err := NewFilter([]string{}, []string{"/meter/*"}, false)
+	if err != nil {
+		t.Fatalf("failed to create filter: %s", err.Error())
+	}
+	var buffer bytes.Buffer
+	out := New(&buffer, "hex", false, filter, "stderr")
+	out.WritePacket(bundle, Source{Protocol: "udp"})
+	out.WritePacket(meter, Source{Protocol: "udp"})
+
+	expected := hex.EncodeToString(bundleBytes) + "\n"
+	if buffer.String() != expected {
+		t.Fatalf("failed to write filtered hex got '%s', expected '%s'", buffer.String(), expected)
+	}
+}
+
+func TestOutputWriteError(t *testing.T) {
+	source := Source{
+		Protocol: "udp",
+		Address:  &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000},
+		Received: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	payload := []byte{0x2f, 0x61}
+	decodeErr := errors.New("OSC string must be null-terminated")
+
+	testCases := []struct {
+		name      string
+		errorMode string
+		out       string
+		errOut    string
+	}{
+		{
+			name:      "stderr",
+			errorMode: "stderr",
+			errOut: "2026-01-01T12:00:00Z 127.0.0.1:9000 malformed udp packet #1: OSC string must be null-terminated (hex: 2f61)\n" +
+				"2026-01-01T12:00:00Z 127.0.0.1:9000 malformed udp packet #2: OSC string must be null-terminated (hex: 2f61)\n",
+		},
+		{
+			name:      "inline",
+			errorMode: "inline",
+			out: `{"time":"2026-01-01T12:00:00Z","source":"127.0.0.1:9000","protocol":"udp","error":"OSC string must be null-terminated","hex":"2f61","errorCount":1}` + "\n" +
+				`{"time":"2026-01-01T12:00:00Z","source":"127.0.0.1:9000","protocol":"udp","error":"OSC string must be null-terminated","hex":"2f61","errorCount":2}` + "\n",
+		},
+		{
+			name:      "ignore",
+			errorMode: "ignore",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var errOut bytes.Buffer
+			output := New(&out, "ndjson", false, nil, testCase.errorMode)
+			output.errWriter = &errOut
+
+			output.WriteError(payload, decodeErr, source)
+			output.WriteError(payload, decodeErr, source)
+			if out.String() != testCase.out {
+				t.Fatalf("failed to write errors got '%s', expected '%s'", out.String(), testCase.out)
+			}
+			if errOut.String() != testCase.errOut {
+				t.Fatalf("failed to report errors got '%s', expected '%s'", errOut.String(), testCase.errOut)
+			}
+		})
+	}
+}
+
+func TestOutputWriteSummary(t *testing.T) {
+	output := New(io.Discard, "json", false, nil, "ignore")
+	message := &osc.OSCMessage{Address: "/a", Args: []osc.OSCArg{}}
+
+	output.WritePacket(message, Source{Protocol: "udp"})
+	output.WritePacket(message, Source{Protocol: "udp"})
+	output.WriteError([]byte{0}, errors.New("bad"), Source{Protocol: "udp"})
+
+	var summary bytes.Buffer
+	output.WriteSummary(&summary)
+	expected := "received 2 packets, 1 malformed\n"
+	if summary.String() != expected {
+		t.Fatalf("failed to summarize got '%s', expected '%s'", summary.String(), expected)
+	}
+}
+
+type recordingWriter struct {
+	packets []osc.OSCPacket
+}
+
+func (w *recordingWriter) WritePacket(packet osc.OSCPacket) error {
+	w.packets = append(w.packets, packet)
+	return nil
+}
+
+func TestNewPacketWriter(t *testing.T) {
+	fader := &osc.OSCMessage{Address: "/ch/1/mix/fader", Args: []osc.OSCArg{}}
+	meter := &osc.OSCMessage{Address: "/meter/1", Args: []osc.OSCArg{}}
+	timeTag := osc.NewOSCTimeTag(3913056000, 0)
+
+	filter, err := NewFilter([]string{}, []string{"/meter/*"}, false)
+	if err != nil {
+		t.Fatalf("failed to create filter: %s", err.Error())
+	}
+	writer := &recordingWriter{}
+	output := NewPacketWriter(writer, filter, "stderr")
+
+	output.WritePacket(&osc.OSCBundle{TimeTag: timeTag, Contents: []osc.OSCPacket{fader, meter}}, Source{Protocol: "stdout"})
+	output.WritePacket(meter, Source{Protocol: "stdout"})
+	output.WritePacket(fader, Source{Protocol: "stdout"})
+
+	expected := []osc.OSCPacket{
+		&osc.OSCBundle{TimeTag: timeTag, Contents: []osc.OSCPacket{fader}},
+		fader,
+	}
+	if !reflect.DeepEqual(writer.packets, expected) {
+		t.Fatalf("failed to write packets got '%v', expected '%v'", writer.packets, expected)
+	}
+
+	var summary bytes.Buffer
+	output.WriteSummary(&summary)
+	if summary.String() != "received 3 packets, 0 malformed\n" {
+		t.Fatalf("failed to count packets got '%s', expected '%s'", summary.String(), "received 3 packets, 0 malformed\n")
+	}
+}
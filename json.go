@@ -0,0 +1,161 @@
+package osc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+type argJSON struct {
+	Value json.RawMessage `json:"value"`
+	Type  string          `json:"type"`
+}
+
+func (a *OSCArg) UnmarshalJSON(data []byte) error {
+	var raw argJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw.Value))
+	decoder.UseNumber()
+
+	var value any
+	if len(raw.Value) > 0 {
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+	}
+
+	arg := OSCArg{Type: raw.Type}
+
+	switch raw.Type {
+	case "s":
+		stringValue, ok := value.(string)
+		if !ok {
+			return errors.New("OSC arg had string type but non-string value")
+		}
+		arg.Value = stringValue
+	case "i":
+		number, ok := value.(json.Number)
+		if !ok {
+			return errors.New("OSC arg had int32 type but non-number value")
+		}
+		intValue, err := number.Int64()
+		if err != nil || intValue < math.MinInt32 || intValue > math.MaxInt32 {
+			return fmt.Errorf("OSC arg had int32 type but value %s is not an int32", number)
+		}
+		arg.Value = int32(intValue)
+	case "h":
+		number, ok := value.(json.Number)
+		if !ok {
+			return errors.New("OSC arg had int64 type but non-number value")
+		}
+		intValue, err := number.Int64()
+		if err != nil {
+			return fmt.Errorf("OSC arg had int64 type but value %s is not an int64", number)
+		}
+		arg.Value = intValue
+	case "f", "d":
+		number, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("OSC arg had %s type but non-number value", raw.Type)
+		}
+		floatValue, err := number.Float64()
+		if err != nil {
+			return err
+		}
+		if raw.Type == "f" {
+			arg.Value = float32(floatValue)
+		} else {
+			arg.Value = floatValue
+		}
+	case "b":
+		var blob []byte
+		if err := json.Unmarshal(raw.Value, &blob); err != nil {
+			return errors.New("OSC arg had blob type but non-blob value")
+		}
+		arg.Value = blob
+	case "T":
+		arg.Value = true
+	case "F":
+		arg.Value = false
+	case "N":
+		arg.Value = nil
+	case "I":
+		arg.Value = math.MaxInt32
+	case "r":
+		var color OSCColor
+		if err := json.Unmarshal(raw.Value, &color); err != nil {
+			return errors.New("OSC arg had color type but non-color value")
+		}
+		arg.Value = color
+	case "t":
+		var timeTag OSCTimeTag
+		if err := json.Unmarshal(raw.Value, &timeTag); err != nil {
+			return errors.New("OSC arg had time tag type but non-time tag value")
+		}
+		arg.Value = timeTag
+	default:
+		return fmt.Errorf("unsupported OSC argument type: %s", raw.Type)
+	}
+
+	*a = arg
+	return nil
+}
+
+type bundleJSON struct {
+	Contents []json.RawMessage `json:"contents"`
+	TimeTag  OSCTimeTag        `json:"timeTag"`
+}
+
+func (b *OSCBundle) UnmarshalJSON(data []byte) error {
+	var raw bundleJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	contents := []OSCPacket{}
+	for _, rawContent := range raw.Contents {
+		content, err := PacketFromJSON(rawContent)
+		if err != nil {
+			return err
+		}
+		contents = append(contents, content)
+	}
+
+	b.TimeTag = raw.TimeTag
+	b.Contents = contents
+	return nil
+}
+
+// PacketFromJSON decodes the JSON representation produced by json.Marshal of a message or bundle
+func PacketFromJSON(data []byte) (OSCPacket, error) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, err
+	}
+
+	if _, ok := keys["address"]; ok {
+		message := &OSCMessage{}
+		if err := json.Unmarshal(data, message); err != nil {
+			return nil, err
+		}
+		if message.Args == nil {
+			message.Args = []OSCArg{}
+		}
+		return message, nil
+	}
+
+	if _, ok := keys["contents"]; ok {
+		bundle := &OSCBundle{}
+		if err := json.Unmarshal(data, bundle); err != nil {
+			return nil, err
+		}
+		return bundle, nil
+	}
+
+	return nil, errors.New("OSC Packet JSON must have an address for a message or contents for a bundle")
+}
@@ -0,0 +1,127 @@
+package osc
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestGoodPacketFromJSON(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		json     string
+		expected OSCPacket
+	}{
+		{
+			name:     "message without args",
+			json:     `{"address":"/hello"}`,
+			expected: &OSCMessage{Address: "/hello", Args: []OSCArg{}},
+		},
+		{
+			name: "message with args",
+			json: `{"address":"/hello","args":[{"type":"s","value":"hi"},{"type":"i","value":35},{"type":"f","value":0.5},{"type":"h","value":281474976710655},{"type":"d","value":12.5},{"type":"b","value":"AQID"},{"type":"T","value":true},{"type":"F","value":false},{"type":"N","value":null},{"type":"I"},{"type":"r","value":{"r":1,"g":2,"b":3,"a":4}},{"type":"t","value":{"seconds":1,"fractionalSeconds":2}}]}`,
+			expected: &OSCMessage{Address: "/hello", Args: []OSCArg{
+				{Type: "s", Value: "hi"},
+				{Type: "i", Value: int32(35)},
+				{Type: "f", Value: float32(0.5)},
+				{Type: "h", Value: int64(281474976710655)},
+				{Type: "d", Value: 12.5},
+				{Type: "b", Value: []byte{1, 2, 3}},
+				{Type: "T", Value: true},
+				{Type: "F", Value: false},
+				{Type: "N", Value: nil},
+				{Type: "I", Value: math.MaxInt32},
+				{Type: "r", Value: OSCColor{r: 1, g: 2, b: 3, a: 4}},
+				{Type: "t", Value: NewOSCTimeTag(1, 2)},
+			}},
+		},
+		{
+			name: "nested bundle",
+			json: `{"timeTag":{"seconds":0,"fractionalSeconds":1},"contents":[{"address":"/a","args":[]},{"timeTag":{"seconds":5,"fractionalSeconds":0},"contents":[{"address":"/b","args":[]}]}]}`,
+			expected: &OSCBundle{
+				TimeTag: ImmediateTimeTag(),
+				Contents: []OSCPacket{
+					&OSCMessage{Address: "/a", Args: []OSCArg{}},
+					&OSCBundle{
+						TimeTag:  NewOSCTimeTag(5, 0),
+						Contents: []OSCPacket{&OSCMessage{Address: "/b", Args: []OSCArg{}}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := PacketFromJSON([]byte(testCase.json))
+
+			if err != nil {
+				t.Fatalf("failed to decode JSON: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to decode JSON properly got '%+v', expected '%+v'", got, testCase.expected)
+			}
+
+			encoded, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("failed to encode JSON: %s", err.Error())
+			}
+
+			roundTrip, err := PacketFromJSON(encoded)
+			if err != nil {
+				t.Fatalf("failed to decode encoded JSON: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(roundTrip, testCase.expected) {
+				t.Fatalf("failed to round trip JSON got '%+v', expected '%+v'", roundTrip, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestBadPacketFromJSON(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		json        string
+		errorString string
+	}{
+		{
+			name:        "neither message nor bundle",
+			json:        `{"foo":1}`,
+			errorString: "OSC Packet JSON must have an address for a message or contents for a bundle",
+		},
+		{
+			name:        "int32 out of range",
+			json:        `{"address":"/a","args":[{"type":"i","value":3000000000}]}`,
+			errorString: "OSC arg had int32 type but value 3000000000 is not an int32",
+		},
+		{
+			name:        "string type with number value",
+			json:        `{"address":"/a","args":[{"type":"s","value":1}]}`,
+			errorString: "OSC arg had string type but non-string value",
+		},
+		{
+			name:        "unsupported type",
+			json:        `{"address":"/a","args":[{"type":"x","value":1}]}`,
+			errorString: "unsupported OSC argument type: x",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := PacketFromJSON([]byte(testCase.json))
+
+			if err == nil {
+				t.Fatalf("PacketFromJSON expected to fail but got: %+v", got)
+			}
+
+			if err.Error() != testCase.errorString {
+				t.Fatalf("PacketFromJSON got error '%s', expected '%s'", err.Error(), testCase.errorString)
+			}
+		})
+	}
+}
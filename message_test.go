@@ -81,6 +81,11 @@ func TestGoodOSCMessageEncoding(t *testing.T) {
 				47, 104, 101, 108, 108, 111, 0, 0, 44, 100, 0, 0, 0x40, 0x29, 0x87, 0xec, 0x82, 0x74, 0xb9, 0xe6,
 			},
 		},
+		{
+			name:     "simple address time tag arg",
+			message:  &OSCMessage{Address: "/hello", Args: []OSCArg{{Type: "t", Value: NewOSCTimeTag(3000000000, 1)}}},
+			expected: []byte{47, 104, 101, 108, 108, 111, 0, 0, 44, 116, 0, 0, 0xb2, 0xd0, 0x5e, 0x00, 0, 0, 0, 1},
+		},
 		// TODO(jwetzell): get array args working working
 		// {
 		// 	name: "simple address array arg",
@@ -206,6 +211,14 @@ func TestBadOSCMessageEncoding(t *testing.T) {
 			},
 			errorString: "OSC arg had float64 type but non-number value",
 		},
+		{
+			name: "time tag arg that is not a time tag",
+			message: &OSCMessage{
+				Address: "/hello",
+				Args:    []OSCArg{{Type: "t", Value: "not a time tag"}},
+			},
+			errorString: "OSC arg had time tag type but non-time tag value",
+		},
 		{
 			name: "blob arg that is not a byte array",
 			message: &OSCMessage{
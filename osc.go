@@ -182,6 +182,12 @@ func argsToBuffer(args []OSCArg) ([]byte, error) {
 			} else {
 				return nil, errors.New("OSC arg had float64 type but non-number value")
 			}
+		case "t":
+			if value, ok := arg.Value.(OSCTimeTag); ok {
+				argBuffers = append(argBuffers, timeTagToOSCBytes(value)...)
+			} else {
+				return nil, errors.New("OSC arg had time tag type but non-time tag value")
+			}
 		default:
 			return nil, fmt.Errorf("unsupported OSC argument type: %s", arg.Type)
 		}
@@ -0,0 +1,211 @@
+package osc
+
+import (
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket that allows Rate messages per second with bursts of up to Burst
+type RateLimit struct {
+	Rate float64
+	// Burst is how many messages can arrive at once, zero allows one second's worth
+	Burst int
+}
+
+func (l RateLimit) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return math.Max(1, math.Ceil(l.Rate))
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		limit:  limit,
+		tokens: limit.burst(),
+		last:   now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.limit.burst(), b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate)
+	b.last = now
+}
+
+type patternLimit struct {
+	pattern *AddressPattern
+	bucket  *tokenBucket
+}
+
+// RateLimitStats counts the messages a RateLimiter has seen
+type RateLimitStats struct {
+	Allowed          int
+	DroppedBySource  int
+	DroppedByPattern int
+}
+
+// RateLimiter is a Handler that drops messages over the per source or per address pattern limits and passes the rest to Handler
+type RateLimiter struct {
+	Handler Handler
+	// PerSource limits the messages from each source IP, zero Rate has no limit
+	PerSource RateLimit
+	// OnDrop is called with each message dropped and the pattern that dropped it, the pattern is empty when the source limit did, nil ignores them
+	OnDrop func(message *OSCMessage, source net.Addr, pattern string)
+
+	patterns  []*patternLimit
+	sources   map[string]*tokenBucket
+	lastPrune time.Time
+	stats     RateLimitStats
+	now       func() time.Time
+	mutex     sync.Mutex
+}
+
+func NewRateLimiter(handler Handler, perSource RateLimit) *RateLimiter {
+	return &RateLimiter{
+		Handler:   handler,
+		PerSource: perSource,
+		patterns:  []*patternLimit{},
+		sources:   map[string]*tokenBucket{},
+		now:       time.Now,
+	}
+}
+
+// LimitPattern shares one limit between every message with an address matching pattern, regardless of source
+func (l *RateLimiter) LimitPattern(pattern string, limit RateLimit) error {
+	addressPattern, err := CompileAddressPattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.patterns = append(l.patterns, &patternLimit{
+		pattern: addressPattern,
+		bucket:  newTokenBucket(limit, l.now()),
+	})
+	return nil
+}
+
+// Stats returns a snapshot of the counts so far
+func (l *RateLimiter) Stats() RateLimitStats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.stats
+}
+
+func (l *RateLimiter) ServeOSC(w PacketWriter, packet OSCPacket, source net.Addr) {
+	if allowed := l.prune(packet, source); allowed != nil {
+		l.Handler.ServeOSC(w, allowed, source)
+	}
+}
+
+// prune returns the packet with the messages over a limit removed or nil if nothing is left
+//
+// The signature message of a bundle written by a Signer isn't counted so a signed packet costs the same as an unsigned
+// one, the signature is kept as long as something it signed is.
+func (l *RateLimiter) prune(packet OSCPacket, source net.Addr) OSCPacket {
+	return rebuildPacket(packet, func(packet OSCPacket) (OSCPacket, bool) {
+		switch packet := packet.(type) {
+		case *OSCMessage:
+			if l.allow(packet, source) {
+				return packet, true
+			}
+			return nil, true
+		case *OSCBundle:
+			signature := signatureMessage(packet)
+			if signature == nil {
+				return nil, false
+			}
+			signed := l.prune(packet.Contents[0], source)
+			if signed == nil {
+				return nil, true
+			}
+			return &OSCBundle{TimeTag: packet.TimeTag, Contents: []OSCPacket{signed, signature}}, true
+		}
+		return nil, false
+	})
+}
+
+func (l *RateLimiter) allow(message *OSCMessage, source net.Addr) bool {
+	l.mutex.Lock()
+	now := l.now()
+	l.pruneSources(now)
+
+	// NOTE: check every bucket before taking from any so a drop doesn't use up another limit
+	buckets := []*tokenBucket{}
+	if l.PerSource.Rate > 0 {
+		key := sourceIP(source)
+		bucket, ok := l.sources[key]
+		if !ok {
+			bucket = newTokenBucket(l.PerSource, now)
+			l.sources[key] = bucket
+		}
+		bucket.refill(now)
+		if bucket.tokens < 1 {
+			l.stats.DroppedBySource++
+			l.mutex.Unlock()
+			l.dropped(message, source, "")
+			return false
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	for _, limit := range l.patterns {
+		if !limit.pattern.Match(message.Address) {
+			continue
+		}
+		limit.bucket.refill(now)
+		if limit.bucket.tokens < 1 {
+			l.stats.DroppedByPattern++
+			l.mutex.Unlock()
+			l.dropped(message, source, limit.pattern.String())
+			return false
+		}
+		buckets = append(buckets, limit.bucket)
+	}
+
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	l.stats.Allowed++
+	l.mutex.Unlock()
+	return true
+}
+
+func (l *RateLimiter) dropped(message *OSCMessage, source net.Addr, pattern string) {
+	if l.OnDrop != nil {
+		l.OnDrop(message, source, pattern)
+	}
+}
+
+// pruneSources forgets sources whose bucket has filled back up so spoofed or one-off sources don't pile up
+func (l *RateLimiter) pruneSources(now time.Time) {
+	if now.Sub(l.lastPrune) < time.Minute {
+		return
+	}
+	l.lastPrune = now
+	for key, bucket := range l.sources {
+		bucket.refill(now)
+		if bucket.tokens >= bucket.limit.burst() {
+			delete(l.sources, key)
+		}
+	}
+}
+
+func sourceIP(source net.Addr) string {
+	if ip := sourceAddrIP(source); ip != nil {
+		return ip.String()
+	}
+	if source == nil {
+		return ""
+	}
+	return source.String()
+}
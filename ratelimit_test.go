@@ -0,0 +1,170 @@
+package osc
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func countingHandler(count *int) Handler {
+	return HandlerFunc(func(w PacketWriter, packet OSCPacket, source net.Addr) {
+		switch packet := packet.(type) {
+		case *OSCMessage:
+			*count++
+		case *OSCBundle:
+			*count += len(packet.Contents)
+		}
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	touchscreen := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 5), Port: 9000}
+	touchscreenOtherPort := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 5), Port: 9001}
+	console := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 6), Port: 9000}
+	fader := &OSCMessage{Address: "/touch/fader", Args: []OSCArg{}}
+	cue := &OSCMessage{Address: "/cue/go", Args: []OSCArg{}}
+	signedFader := &OSCBundle{TimeTag: ImmediateTimeTag(), Contents: []OSCPacket{fader, &OSCMessage{Address: SignatureAddress, Args: []OSCArg{}}}}
+
+	type send struct {
+		after   time.Duration
+		packet  OSCPacket
+		source  net.Addr
+		allowed int
+	}
+
+	testCases := []struct {
+		name      string
+		perSource RateLimit
+		patterns  map[string]RateLimit
+		sends     []send
+		expected  RateLimitStats
+	}{
+		{
+			name:      "source burst then refill",
+			perSource: RateLimit{Rate: 10, Burst: 2},
+			sends: []send{
+				{packet: fader, source: touchscreen, allowed: 1},
+				{packet: fader, source: touchscreen, allowed: 1},
+				{packet: fader, source: touchscreen, allowed: 0},
+				{after: 100 * time.Millisecond, packet: fader, source: touchscreen, allowed: 1},
+			},
+			expected: RateLimitStats{Allowed: 3, DroppedBySource: 1},
+		},
+		{
+			name:      "sources are limited by IP",
+			perSource: RateLimit{Rate: 1, Burst: 1},
+			sends: []send{
+				{packet: fader, source: touchscreen, allowed: 1},
+				{packet: fader, source: touchscreenOtherPort, allowed: 0},
+				{packet: fader, source: console, allowed: 1},
+			},
+			expected: RateLimitStats{Allowed: 2, DroppedBySource: 1},
+		},
+		{
+			name:     "pattern limit only applies to matching addresses",
+			patterns: map[string]RateLimit{"/touch/*": {Rate: 1, Burst: 1}},
+			sends: []send{
+				{packet: fader, source: touchscreen, allowed: 1},
+				{packet: fader, source: console, allowed: 0},
+				{packet: cue, source: console, allowed: 1},
+			},
+			expected: RateLimitStats{Allowed: 2, DroppedByPattern: 1},
+		},
+		{
+			name:     "bundles are pruned",
+			patterns: map[string]RateLimit{"/touch/*": {Rate: 1, Burst: 1}},
+			sends: []send{
+				{packet: &OSCBundle{TimeTag: ImmediateTimeTag(), Contents: []OSCPacket{fader, fader, cue}}, source: touchscreen, allowed: 2},
+			},
+			expected: RateLimitStats{Allowed: 2, DroppedByPattern: 1},
+		},
+		{
+			name:      "a pattern drop doesn't use up the source limit",
+			perSource: RateLimit{Rate: 1, Burst: 2},
+			patterns:  map[string]RateLimit{"/touch/*": {Rate: 1, Burst: 1}},
+			sends: []send{
+				{packet: fader, source: touchscreen, allowed: 1},
+				{packet: fader, source: touchscreen, allowed: 0},
+				{packet: cue, source: touchscreen, allowed: 1},
+			},
+			expected: RateLimitStats{Allowed: 2, DroppedByPattern: 1},
+		},
+		{
+			name:      "signatures aren't counted",
+			perSource: RateLimit{Rate: 1, Burst: 1},
+			sends: []send{
+				{packet: signedFader, source: touchscreen, allowed: 2},
+				{packet: signedFader, source: touchscreen, allowed: 0},
+			},
+			expected: RateLimitStats{Allowed: 1, DroppedBySource: 1},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			count := 0
+			clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+			limiter := NewRateLimiter(countingHandler(&count), testCase.perSource)
+			limiter.now = clock.Now
+			for pattern, limit := range testCase.patterns {
+				err := limiter.LimitPattern(pattern, limit)
+				if err != nil {
+					t.Fatalf("failed to add pattern limit: %s", err.Error())
+				}
+			}
+
+			for index, send := range testCase.sends {
+				clock.now = clock.now.Add(send.after)
+				count = 0
+				limiter.ServeOSC(nil, send.packet, send.source)
+				if count != send.allowed {
+					t.Fatalf("failed to limit send %d got %d messages through, expected %d", index, count, send.allowed)
+				}
+			}
+
+			if limiter.Stats() != testCase.expected {
+				t.Fatalf("failed to count got '%+v', expected '%+v'", limiter.Stats(), testCase.expected)
+			}
+		})
+	}
+}
+
+func TestRateLimiterOnDrop(t *testing.T) {
+	count := 0
+	limiter := NewRateLimiter(countingHandler(&count), RateLimit{})
+	err := limiter.LimitPattern("/touch/*", RateLimit{Rate: 1, Burst: 1})
+	if err != nil {
+		t.Fatalf("failed to add pattern limit: %s", err.Error())
+	}
+
+	droppedPattern := ""
+	limiter.OnDrop = func(message *OSCMessage, source net.Addr, pattern string) {
+		droppedPattern = pattern
+	}
+
+	fader := &OSCMessage{Address: "/touch/fader", Args: []OSCArg{}}
+	limiter.ServeOSC(nil, fader, nil)
+	limiter.ServeOSC(nil, fader, nil)
+	if droppedPattern != "/touch/*" {
+		t.Fatalf("failed to report drop got '%s', expected '%s'", droppedPattern, "/touch/*")
+	}
+}
+
+func TestRateLimiterBadPattern(t *testing.T) {
+	limiter := NewRateLimiter(nil, RateLimit{})
+	err := limiter.LimitPattern("touch", RateLimit{Rate: 1})
+	if err == nil {
+		t.Fatalf("expected bad pattern to fail")
+	}
+	if err.Error() != "OSC address pattern must start with /" {
+		t.Fatalf("failed to reject pattern got '%s', expected '%s'", err.Error(), "OSC address pattern must start with /")
+	}
+}
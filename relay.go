@@ -0,0 +1,266 @@
+package osc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+type relayRoute struct {
+	conn     Conn
+	patterns []*AddressPattern
+	// NOTE: replies read from conn go back to whoever last sent a packet through this route
+	source PacketWriter
+}
+
+type forwardedPacket struct {
+	origin  string
+	count   int
+	expires time.Time
+}
+
+// Relay is a Handler that forwards received packets to one or more destination Conns
+type Relay struct {
+	// LoopWindow drops an incoming packet identical to one the relay forwarded within the window unless it came from the same source, zero disables it
+	LoopWindow time.Duration
+	// Rules rewrites packets before they are routed, nil forwards them unchanged
+	Rules *RuleSet
+	// ErrorHandler is called with errors rewriting packets, writing to a destination or reading from one, nil ignores them
+	ErrorHandler func(err error, addr net.Addr)
+
+	routes    []*relayRoute
+	forwarded map[string]*forwardedPacket
+	lastPrune time.Time
+	mutex     sync.Mutex
+}
+
+func NewRelay() *Relay {
+	return &Relay{
+		routes:    []*relayRoute{},
+		forwarded: map[string]*forwardedPacket{},
+	}
+}
+
+// AddRoute forwards packets to conn, if any patterns are given only messages with a matching address are forwarded
+func (r *Relay) AddRoute(conn Conn, patterns ...string) error {
+	route := &relayRoute{
+		conn:     conn,
+		patterns: []*AddressPattern{},
+	}
+	for _, pattern := range patterns {
+		addressPattern, err := CompileAddressPattern(pattern)
+		if err != nil {
+			return err
+		}
+		route.patterns = append(route.patterns, addressPattern)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.routes = append(r.routes, route)
+	return nil
+}
+
+func (r *Relay) ServeOSC(w PacketWriter, packet OSCPacket, source net.Addr) {
+	if r.looped(packet, source) {
+		return
+	}
+
+	if r.Rules != nil {
+		rewritten, err := r.Rules.ApplyPacket(packet)
+		if err != nil {
+			r.reportError(err, source)
+			return
+		}
+		if rewritten == nil {
+			return
+		}
+		packet = rewritten
+	}
+
+	r.mutex.Lock()
+	routes := r.routes
+	r.mutex.Unlock()
+
+	for _, route := range routes {
+		if source != nil && sameAddr(remoteAddr(route.conn), source) {
+			continue
+		}
+
+		routed := route.prune(packet)
+		if routed == nil {
+			continue
+		}
+
+		r.mutex.Lock()
+		route.source = w
+		r.mutex.Unlock()
+
+		r.forward(route.conn, routed, source, remoteAddr(route.conn))
+	}
+}
+
+// ServeReplies reads packets from a destination added with AddRoute and forwards them back to the last source that sent to it, it returns when conn is closed
+func (r *Relay) ServeReplies(conn Conn) error {
+	var route *relayRoute
+	r.mutex.Lock()
+	for _, candidate := range r.routes {
+		if candidate.conn == conn {
+			route = candidate
+		}
+	}
+	r.mutex.Unlock()
+	if route == nil {
+		return errors.New("relay has no route for connection")
+	}
+
+	for {
+		packet, dest, err := conn.ReadPacket()
+		if err != nil {
+			var decodeErr *DecodeError
+			if errors.As(err, &decodeErr) {
+				r.reportError(err, dest)
+				continue
+			}
+			if errors.Is(err, net.ErrClosed) || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		r.mutex.Lock()
+		source := route.source
+		r.mutex.Unlock()
+		if source == nil {
+			continue
+		}
+		r.forward(source, packet, dest, dest)
+	}
+}
+
+// Close closes every destination Conn
+func (r *Relay) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	errs := []error{}
+	for _, route := range r.routes {
+		errs = append(errs, route.conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Relay) forward(w PacketWriter, packet OSCPacket, origin net.Addr, dest net.Addr) {
+	if r.LoopWindow > 0 {
+		packetBytes, err := packet.ToBytes()
+		if err != nil {
+			r.reportError(err, dest)
+			return
+		}
+		r.remember(packetBytes, origin)
+	}
+	if err := w.WritePacket(packet); err != nil {
+		r.reportError(err, dest)
+	}
+}
+
+func (r *Relay) remember(packetBytes []byte, origin net.Addr) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	r.pruneForwarded(now)
+
+	key := string(packetBytes)
+	forwarded, ok := r.forwarded[key]
+	if !ok {
+		forwarded = &forwardedPacket{origin: addrString(origin)}
+		r.forwarded[key] = forwarded
+	}
+	forwarded.count++
+	forwarded.expires = now.Add(r.LoopWindow)
+}
+
+// pruneForwarded forgets expired packets at most once per LoopWindow, looped checks expiry itself in between
+func (r *Relay) pruneForwarded(now time.Time) {
+	if now.Sub(r.lastPrune) < r.LoopWindow {
+		return
+	}
+	r.lastPrune = now
+	for key, forwarded := range r.forwarded {
+		if now.After(forwarded.expires) {
+			delete(r.forwarded, key)
+		}
+	}
+}
+
+// looped reports whether packet is a copy of one the relay itself recently forwarded coming back from somewhere else
+func (r *Relay) looped(packet OSCPacket, source net.Addr) bool {
+	if r.LoopWindow <= 0 {
+		return false
+	}
+	packetBytes, err := packet.ToBytes()
+	if err != nil {
+		return false
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := string(packetBytes)
+	forwarded, ok := r.forwarded[key]
+	// NOTE: a source repeating itself is not a loop, faders often send the same value many times
+	if !ok || time.Now().After(forwarded.expires) || forwarded.origin == addrString(source) {
+		return false
+	}
+	forwarded.count--
+	if forwarded.count == 0 {
+		delete(r.forwarded, key)
+	}
+	return true
+}
+
+func (r *Relay) reportError(err error, addr net.Addr) {
+	if r.ErrorHandler != nil {
+		r.ErrorHandler(err, addr)
+	}
+}
+
+// prune returns the packet with messages that don't match the route removed or nil if nothing is left
+func (route *relayRoute) prune(packet OSCPacket) OSCPacket {
+	if len(route.patterns) == 0 {
+		return packet
+	}
+
+	return pruneMessages(packet, func(message *OSCMessage) bool {
+		for _, pattern := range route.patterns {
+			if pattern.Match(message.Address) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func remoteAddr(conn Conn) net.Addr {
+	if connected, ok := conn.(interface{ RemoteAddr() net.Addr }); ok {
+		return connected.RemoteAddr()
+	}
+	return nil
+}
+
+func addrString(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+	return addr.Network() + " " + addr.String()
+}
+
+func sameAddr(a net.Addr, b net.Addr) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.Network() == b.Network() && a.String() == b.String()
+}
@@ -0,0 +1,200 @@
+package osc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRelayRoutes(t *testing.T) {
+	faderMessage := &OSCMessage{Address: "/mixer/fader/1", Args: []OSCArg{{Type: "f", Value: float32(0.5)}}}
+	cueMessage := &OSCMessage{Address: "/eos/cue/fire", Args: []OSCArg{{Type: "i", Value: int32(1)}}}
+
+	testCases := []struct {
+		name     string
+		patterns []string
+		packet   OSCPacket
+		expected OSCPacket
+	}{
+		{
+			name:     "no patterns forwards everything",
+			packet:   faderMessage,
+			expected: faderMessage,
+		},
+		{
+			name:     "matching message",
+			patterns: []string{"/mixer/*/*"},
+			packet:   faderMessage,
+			expected: faderMessage,
+		},
+		{
+			name:     "non-matching message",
+			patterns: []string{"/mixer/*/*"},
+			packet:   cueMessage,
+			expected: nil,
+		},
+		{
+			name:     "bundle is pruned to matching messages",
+			patterns: []string{"/eos/*/*"},
+			packet: &OSCBundle{
+				TimeTag:  ImmediateTimeTag(),
+				Contents: []OSCPacket{faderMessage, cueMessage},
+			},
+			expected: &OSCBundle{
+				TimeTag:  ImmediateTimeTag(),
+				Contents: []OSCPacket{cueMessage},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			dest, err := ListenUDP("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("failed to listen: %s", err.Error())
+			}
+			defer dest.Close()
+
+			destConn, err := DialUDP("udp", dest.LocalAddr().String())
+			if err != nil {
+				t.Fatalf("failed to dial: %s", err.Error())
+			}
+
+			relay := NewRelay()
+			defer relay.Close()
+			err = relay.AddRoute(destConn, testCase.patterns...)
+			if err != nil {
+				t.Fatalf("failed to add route: %s", err.Error())
+			}
+
+			relay.ServeOSC(nil, testCase.packet, nil)
+
+			dest.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			got, _, err := dest.ReadPacket()
+			if testCase.expected == nil {
+				if err == nil {
+					t.Fatalf("failed to filter packet got '%v', expected nothing", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to read forwarded packet: %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to forward packet got '%v', expected '%v'", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestRelayBadPattern(t *testing.T) {
+	relay := NewRelay()
+	err := relay.AddRoute(nil, "mixer")
+	if err == nil {
+		t.Fatalf("expected bad pattern to fail")
+	}
+	if err.Error() != "OSC address pattern must start with /" {
+		t.Fatalf("failed to reject pattern got '%s', expected '%s'", err.Error(), "OSC address pattern must start with /")
+	}
+}
+
+func TestRelayBidirectional(t *testing.T) {
+	device, err := ListenUDP("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer device.Close()
+	go (&Server{Handler: echoHandler(t)}).Serve(device)
+
+	deviceConn, err := DialUDP("udp", device.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+
+	relay := NewRelay()
+	defer relay.Close()
+	err = relay.AddRoute(deviceConn)
+	if err != nil {
+		t.Fatalf("failed to add route: %s", err.Error())
+	}
+	go relay.ServeReplies(deviceConn)
+
+	bridge, err := ListenUDP("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer bridge.Close()
+	go (&Server{Handler: relay}).Serve(bridge)
+
+	client, err := DialUDP("udp", bridge.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	defer client.Close()
+
+	message := &OSCMessage{Address: "/ping", Args: []OSCArg{{Type: "s", Value: "hello"}}}
+	err = client.WritePacket(message)
+	if err != nil {
+		t.Fatalf("failed to write packet: %s", err.Error())
+	}
+
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	got, _, err := client.ReadPacket()
+	if err != nil {
+		t.Fatalf("failed to read reply: %s", err.Error())
+	}
+	if !reflect.DeepEqual(got, message) {
+		t.Fatalf("failed to relay reply got '%v', expected '%v'", got, message)
+	}
+}
+
+func TestRelayLoopWindow(t *testing.T) {
+	dest, err := ListenUDP("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer dest.Close()
+
+	destConn, err := DialUDP("udp", dest.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+
+	relay := NewRelay()
+	relay.LoopWindow = time.Second
+	defer relay.Close()
+	err = relay.AddRoute(destConn)
+	if err != nil {
+		t.Fatalf("failed to add route: %s", err.Error())
+	}
+
+	console := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9000}
+	otherBridge := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 9001}
+	message := &OSCMessage{Address: "/loop", Args: []OSCArg{{Type: "i", Value: int32(1)}}}
+
+	testCases := []struct {
+		name      string
+		source    net.Addr
+		forwarded bool
+	}{
+		{name: "first packet", source: console, forwarded: true},
+		{name: "repeat from the same source", source: console, forwarded: true},
+		{name: "copy from somewhere else", source: otherBridge, forwarded: false},
+		{name: "copy after loop was dropped", source: otherBridge, forwarded: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			relay.ServeOSC(nil, message, testCase.source)
+			dest.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			got, _, err := dest.ReadPacket()
+			if testCase.forwarded && err != nil {
+				t.Fatalf("failed to read forwarded packet: %s", err.Error())
+			}
+			if !testCase.forwarded && err == nil {
+				t.Fatalf("failed to drop looped packet got '%v'", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+package osc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Rule rewrites messages with an address matching the Match pattern, each wildcard in Match is a capture numbered from 1
+type Rule struct {
+	Match string `json:"match"`
+	// Address is the new address where {N} is replaced by capture N and {N:TYPE} by capture N converted to an OSC type, empty keeps the address
+	Address string `json:"address"`
+	// Args builds the new arguments in order, empty keeps the original arguments
+	Args []ArgRule `json:"args"`
+
+	pattern *AddressPattern
+}
+
+// ArgRule builds one argument from an incoming argument, an address capture or a constant
+type ArgRule struct {
+	// Arg is the index of the incoming argument starting from 0
+	Arg *int `json:"arg"`
+	// Capture is the address capture starting from 1, it is a string until converted with Type
+	Capture *int `json:"capture"`
+	// Value is a constant argument
+	Value *OSCArg `json:"value"`
+	// Type converts the argument to another OSC type, empty keeps the type
+	Type string `json:"type"`
+	// Scale maps the argument from one range to another
+	Scale *Scale `json:"scale"`
+	// Clamp limits the argument to [min, max] after scaling
+	Clamp []float64 `json:"clamp"`
+}
+
+// Scale maps a number from the In range to the Out range
+type Scale struct {
+	In  [2]float64 `json:"in"`
+	Out [2]float64 `json:"out"`
+	// Curve is linear or log, log spaces the Out range logarithmically for things like frequencies
+	Curve string `json:"curve"`
+}
+
+// RuleSet rewrites each message with the first Rule that matches it
+//
+// Rules are checked and compiled by NewRuleSet and RuleSetFromJSON or else the first time the RuleSet is applied, so
+// they shouldn't be changed after that.
+type RuleSet struct {
+	Rules []*Rule `json:"rules"`
+	// DropUnmatched drops messages no rule matches instead of passing them through unchanged
+	DropUnmatched bool `json:"dropUnmatched"`
+
+	compileOnce sync.Once
+	compileErr  error
+}
+
+var templateCapture = regexp.MustCompile(`\{(\d+)(?::(\w))?\}`)
+
+// NewRuleSet checks and compiles rules, messages no rule matches are passed through unchanged
+func NewRuleSet(rules ...*Rule) (*RuleSet, error) {
+	ruleSet := &RuleSet{Rules: rules}
+	if err := ruleSet.compile(); err != nil {
+		return nil, err
+	}
+	return ruleSet, nil
+}
+
+// RuleSetFromJSON decodes and compiles a rules file like {"rules": [{"match": "/ch/*/mix/fader", "address": "/mixer/{1:i}/level"}]}
+func RuleSetFromJSON(data []byte) (*RuleSet, error) {
+	ruleSet := &RuleSet{}
+	if err := json.Unmarshal(data, ruleSet); err != nil {
+		return nil, err
+	}
+	if err := ruleSet.compile(); err != nil {
+		return nil, err
+	}
+	return ruleSet, nil
+}
+
+func (rs *RuleSet) compile() error {
+	rs.compileOnce.Do(func() {
+		for index, rule := range rs.Rules {
+			if err := rule.compile(); err != nil {
+				rs.compileErr = fmt.Errorf("OSC rule %d: %w", index+1, err)
+				return
+			}
+		}
+	})
+	return rs.compileErr
+}
+
+// Apply rewrites message with the first matching rule, it returns nil if the message is dropped
+func (rs *RuleSet) Apply(message *OSCMessage) (*OSCMessage, error) {
+	if err := rs.compile(); err != nil {
+		return nil, err
+	}
+	for _, rule := range rs.Rules {
+		captures := rule.pattern.Captures(message.Address)
+		if captures == nil {
+			continue
+		}
+		return rule.apply(message, captures)
+	}
+	if rs.DropUnmatched {
+		return nil, nil
+	}
+	return message, nil
+}
+
+// ApplyPacket rewrites every message in packet, it returns nil if every message is dropped
+func (rs *RuleSet) ApplyPacket(packet OSCPacket) (OSCPacket, error) {
+	var applyErr error
+	applied := rebuildPacket(packet, func(packet OSCPacket) (OSCPacket, bool) {
+		message, ok := packet.(*OSCMessage)
+		if !ok {
+			return nil, false
+		}
+		if applyErr != nil {
+			return nil, true
+		}
+		rewritten, err := rs.Apply(message)
+		if rewritten == nil || err != nil {
+			applyErr = err
+			return nil, true
+		}
+		return rewritten, true
+	})
+	if applyErr != nil {
+		return nil, applyErr
+	}
+	return applied, nil
+}
+
+func (r *Rule) compile() error {
+	pattern, err := CompileAddressPattern(r.Match)
+	if err != nil {
+		return err
+	}
+	r.pattern = pattern
+
+	if r.Address != "" && !strings.HasPrefix(r.Address, "/") {
+		return errors.New("OSC rule address must start with /")
+	}
+	for _, reference := range templateCapture.FindAllStringSubmatch(r.Address, -1) {
+		capture, _ := strconv.Atoi(reference[1])
+		if capture < 1 || capture > pattern.NumCaptures() {
+			return fmt.Errorf("OSC rule address refers to capture %d but match has %d", capture, pattern.NumCaptures())
+		}
+	}
+
+	for _, argRule := range r.Args {
+		if err := argRule.check(pattern.NumCaptures()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Rule) apply(message *OSCMessage, captures []string) (*OSCMessage, error) {
+	rewritten := &OSCMessage{
+		Address: message.Address,
+		Args:    message.Args,
+	}
+
+	if r.Address != "" {
+		var templateErr error
+		rewritten.Address = templateCapture.ReplaceAllStringFunc(r.Address, func(reference string) string {
+			parts := templateCapture.FindStringSubmatch(reference)
+			capture, _ := strconv.Atoi(parts[1])
+			value := captures[capture-1]
+			if parts[2] == "" {
+				return value
+			}
+			arg, err := convertArg(OSCArg{Type: "s", Value: value}, parts[2])
+			if err != nil {
+				templateErr = err
+				return ""
+			}
+			if stringValue, ok := arg.Value.(string); ok {
+				return stringValue
+			}
+			return arg.String()
+		})
+		if templateErr != nil {
+			return nil, templateErr
+		}
+	}
+
+	if len(r.Args) > 0 {
+		rewritten.Args = []OSCArg{}
+		for _, argRule := range r.Args {
+			arg, err := argRule.build(message.Args, captures)
+			if err != nil {
+				return nil, err
+			}
+			rewritten.Args = append(rewritten.Args, arg)
+		}
+	}
+	return rewritten, nil
+}
+
+func (a ArgRule) check(numCaptures int) error {
+	sources := 0
+	if a.Arg != nil {
+		sources++
+		if *a.Arg < 0 {
+			return fmt.Errorf("OSC rule arg index %d is negative", *a.Arg)
+		}
+	}
+	if a.Capture != nil {
+		sources++
+		if *a.Capture < 1 || *a.Capture > numCaptures {
+			return fmt.Errorf("OSC rule arg refers to capture %d but match has %d", *a.Capture, numCaptures)
+		}
+	}
+	if a.Value != nil {
+		sources++
+	}
+	if sources != 1 {
+		return errors.New("OSC rule arg must set exactly one of arg, capture or value")
+	}
+
+	if a.Scale != nil {
+		if a.Scale.In[0] == a.Scale.In[1] {
+			return errors.New("OSC rule scale in range must not be empty")
+		}
+		switch a.Scale.Curve {
+		case "", "linear":
+		case "log":
+			if a.Scale.Out[0] <= 0 || a.Scale.Out[1] <= 0 {
+				return errors.New("OSC rule log scale out range must be positive")
+			}
+		default:
+			return fmt.Errorf("unsupported OSC rule scale curve: %s", a.Scale.Curve)
+		}
+	}
+	if a.Clamp != nil && (len(a.Clamp) != 2 || a.Clamp[0] > a.Clamp[1]) {
+		return errors.New("OSC rule clamp must be [min, max]")
+	}
+	return nil
+}
+
+func (a ArgRule) build(args []OSCArg, captures []string) (OSCArg, error) {
+	var arg OSCArg
+	switch {
+	case a.Arg != nil:
+		if *a.Arg >= len(args) {
+			return OSCArg{}, fmt.Errorf("OSC rule arg %d is out of range for message with %d args", *a.Arg, len(args))
+		}
+		arg = args[*a.Arg]
+	case a.Capture != nil:
+		arg = OSCArg{Type: "s", Value: captures[*a.Capture-1]}
+	case a.Value != nil:
+		arg = *a.Value
+	}
+
+	if a.Scale != nil || a.Clamp != nil {
+		number, err := argNumber(arg)
+		if err != nil {
+			return OSCArg{}, err
+		}
+		if a.Scale != nil {
+			number = a.Scale.apply(number)
+		}
+		if a.Clamp != nil {
+			number = math.Max(a.Clamp[0], math.Min(a.Clamp[1], number))
+		}
+		if a.Type != "" {
+			return numberArg(number, a.Type)
+		}
+		// NOTE: keep the incoming number type, strings and bools become floats
+		switch arg.Type {
+		case "i", "h", "f", "d":
+			return numberArg(number, arg.Type)
+		}
+		return numberArg(number, "f")
+	}
+
+	if a.Type != "" {
+		return convertArg(arg, a.Type)
+	}
+	return arg, nil
+}
+
+func (s *Scale) apply(number float64) float64 {
+	position := (number - s.In[0]) / (s.In[1] - s.In[0])
+	if s.Curve == "log" {
+		return s.Out[0] * math.Pow(s.Out[1]/s.Out[0], position)
+	}
+	return s.Out[0] + position*(s.Out[1]-s.Out[0])
+}
+
+// argNumber reads a number out of a numeric, bool or numeric string argument
+func argNumber(arg OSCArg) (float64, error) {
+	switch value := arg.Value.(type) {
+	case int:
+		return float64(value), nil
+	case int32:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case float32:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	case bool:
+		if value {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("OSC arg value %q is not a number", value)
+		}
+		return number, nil
+	}
+	return 0, fmt.Errorf("OSC arg with type %s is not a number", arg.Type)
+}
+
+func numberArg(number float64, oscType string) (OSCArg, error) {
+	switch oscType {
+	case "i":
+		rounded := math.Round(number)
+		if math.IsNaN(rounded) || rounded < math.MinInt32 || rounded > math.MaxInt32 {
+			return OSCArg{}, fmt.Errorf("OSC arg value %v does not fit in an int32", number)
+		}
+		return OSCArg{Type: "i", Value: int32(rounded)}, nil
+	case "h":
+		rounded := math.Round(number)
+		// NOTE: float64(math.MaxInt64) rounds up to 2^63 which is already out of range
+		if math.IsNaN(rounded) || rounded < math.MinInt64 || rounded >= math.MaxInt64 {
+			return OSCArg{}, fmt.Errorf("OSC arg value %v does not fit in an int64", number)
+		}
+		return OSCArg{Type: "h", Value: int64(rounded)}, nil
+	case "f":
+		return OSCArg{Type: "f", Value: float32(number)}, nil
+	case "d":
+		return OSCArg{Type: "d", Value: number}, nil
+	case "s":
+		return OSCArg{Type: "s", Value: strconv.FormatFloat(number, 'g', -1, 64)}, nil
+	case "T", "F":
+		if number != 0 {
+			return OSCArg{Type: "T", Value: true}, nil
+		}
+		return OSCArg{Type: "F", Value: false}, nil
+	}
+	return OSCArg{}, fmt.Errorf("cannot convert a number to OSC type %s", oscType)
+}
+
+// convertArg changes arg to oscType, T and F both mean a bool that is T when the value is non-zero
+func convertArg(arg OSCArg, oscType string) (OSCArg, error) {
+	if arg.Type == oscType {
+		return arg, nil
+	}
+	switch oscType {
+	case "N":
+		return OSCArg{Type: "N", Value: nil}, nil
+	case "I":
+		return OSCArg{Type: "I", Value: math.MaxInt32}, nil
+	case "s":
+		if stringValue, ok := arg.Value.(string); ok {
+			return OSCArg{Type: "s", Value: stringValue}, nil
+		}
+	case "T", "F":
+		if _, ok := arg.Value.(bool); ok {
+			return arg, nil
+		}
+	}
+	number, err := argNumber(arg)
+	if err != nil {
+		return OSCArg{}, err
+	}
+	return numberArg(number, oscType)
+}
@@ -0,0 +1,309 @@
+package osc
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRuleSetApply(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		rules    string
+		message  *OSCMessage
+		expected *OSCMessage
+	}{
+		{
+			name:     "fader to level",
+			rules:    `{"rules": [{"match": "/ch/*/mix/fader", "address": "/mixer/{1:i}/level", "args": [{"arg": 0, "type": "i", "scale": {"in": [0, 1], "out": [0, 127]}}]}]}`,
+			message:  &OSCMessage{Address: "/ch/01/mix/fader", Args: []OSCArg{{Type: "f", Value: float32(0.75)}}},
+			expected: &OSCMessage{Address: "/mixer/1/level", Args: []OSCArg{{Type: "i", Value: int32(95)}}},
+		},
+		{
+			name:     "capture as string",
+			rules:    `{"rules": [{"match": "/{mix,eq}/on", "address": "/{1}/enable"}]}`,
+			message:  &OSCMessage{Address: "/eq/on", Args: []OSCArg{{Type: "T", Value: true}}},
+			expected: &OSCMessage{Address: "/eq/enable", Args: []OSCArg{{Type: "T", Value: true}}},
+		},
+		{
+			name:     "reorder args and add a constant",
+			rules:    `{"rules": [{"match": "/pan", "args": [{"arg": 1}, {"arg": 0}, {"value": {"type": "s", "value": "pan"}}]}]}`,
+			message:  &OSCMessage{Address: "/pan", Args: []OSCArg{{Type: "i", Value: int32(3)}, {Type: "f", Value: float32(-0.5)}}},
+			expected: &OSCMessage{Address: "/pan", Args: []OSCArg{{Type: "f", Value: float32(-0.5)}, {Type: "i", Value: int32(3)}, {Type: "s", Value: "pan"}}},
+		},
+		{
+			name:     "capture as arg",
+			rules:    `{"rules": [{"match": "/ch/*/mute", "address": "/mute", "args": [{"capture": 1, "type": "i"}, {"arg": 0, "type": "T"}]}]}`,
+			message:  &OSCMessage{Address: "/ch/07/mute", Args: []OSCArg{{Type: "i", Value: int32(1)}}},
+			expected: &OSCMessage{Address: "/mute", Args: []OSCArg{{Type: "i", Value: int32(7)}, {Type: "T", Value: true}}},
+		},
+		{
+			name:     "log scale",
+			rules:    `{"rules": [{"match": "/eq/freq", "args": [{"arg": 0, "type": "d", "scale": {"in": [0, 1], "out": [20, 20000], "curve": "log"}}]}]}`,
+			message:  &OSCMessage{Address: "/eq/freq", Args: []OSCArg{{Type: "f", Value: float32(0.5)}}},
+			expected: &OSCMessage{Address: "/eq/freq", Args: []OSCArg{{Type: "d", Value: float64(632.4555320336758)}}},
+		},
+		{
+			name:     "clamp keeps the incoming type",
+			rules:    `{"rules": [{"match": "/level", "args": [{"arg": 0, "clamp": [0, 100]}]}]}`,
+			message:  &OSCMessage{Address: "/level", Args: []OSCArg{{Type: "i", Value: int32(140)}}},
+			expected: &OSCMessage{Address: "/level", Args: []OSCArg{{Type: "i", Value: int32(100)}}},
+		},
+		{
+			name:     "scale a Go int",
+			rules:    `{"rules": [{"match": "/level", "args": [{"arg": 0, "scale": {"in": [0, 10], "out": [0, 100]}}]}]}`,
+			message:  &OSCMessage{Address: "/level", Args: []OSCArg{{Type: "i", Value: 3}}},
+			expected: &OSCMessage{Address: "/level", Args: []OSCArg{{Type: "i", Value: int32(30)}}},
+		},
+		{
+			name:     "convert a Go int",
+			rules:    `{"rules": [{"match": "/level", "args": [{"arg": 0, "type": "f"}]}]}`,
+			message:  &OSCMessage{Address: "/level", Args: []OSCArg{{Type: "i", Value: 3}}},
+			expected: &OSCMessage{Address: "/level", Args: []OSCArg{{Type: "f", Value: float32(3)}}},
+		},
+		{
+			name:     "number to string",
+			rules:    `{"rules": [{"match": "/cue", "args": [{"arg": 0, "type": "s"}]}]}`,
+			message:  &OSCMessage{Address: "/cue", Args: []OSCArg{{Type: "f", Value: float32(1.5)}}},
+			expected: &OSCMessage{Address: "/cue", Args: []OSCArg{{Type: "s", Value: "1.5"}}},
+		},
+		{
+			name:     "first matching rule wins",
+			rules:    `{"rules": [{"match": "/a/*", "address": "/first"}, {"match": "/a/b", "address": "/second"}]}`,
+			message:  &OSCMessage{Address: "/a/b", Args: []OSCArg{}},
+			expected: &OSCMessage{Address: "/first", Args: []OSCArg{}},
+		},
+		{
+			name:     "unmatched passes through",
+			rules:    `{"rules": [{"match": "/a", "address": "/b"}]}`,
+			message:  &OSCMessage{Address: "/c", Args: []OSCArg{}},
+			expected: &OSCMessage{Address: "/c", Args: []OSCArg{}},
+		},
+		{
+			name:     "unmatched dropped",
+			rules:    `{"rules": [{"match": "/a", "address": "/b"}], "dropUnmatched": true}`,
+			message:  &OSCMessage{Address: "/c", Args: []OSCArg{}},
+			expected: nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ruleSet, err := RuleSetFromJSON([]byte(testCase.rules))
+			if err != nil {
+				t.Fatalf("failed to load rules: %s", err.Error())
+			}
+
+			got, err := ruleSet.Apply(testCase.message)
+			if err != nil {
+				t.Fatalf("failed to apply rules: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to rewrite message got '%v', expected '%v'", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestRuleSetApplyPacket(t *testing.T) {
+	ruleSet, err := NewRuleSet(&Rule{Match: "/keep", Address: "/kept"})
+	if err != nil {
+		t.Fatalf("failed to compile rules: %s", err.Error())
+	}
+	ruleSet.DropUnmatched = true
+
+	bundle := &OSCBundle{
+		TimeTag: ImmediateTimeTag(),
+		Contents: []OSCPacket{
+			&OSCMessage{Address: "/keep", Args: []OSCArg{}},
+			&OSCMessage{Address: "/drop", Args: []OSCArg{}},
+		},
+	}
+	expected := &OSCBundle{
+		TimeTag:  ImmediateTimeTag(),
+		Contents: []OSCPacket{&OSCMessage{Address: "/kept", Args: []OSCArg{}}},
+	}
+
+	got, err := ruleSet.ApplyPacket(bundle)
+	if err != nil {
+		t.Fatalf("failed to apply rules: %s", err.Error())
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("failed to rewrite bundle got '%v', expected '%v'", got, expected)
+	}
+}
+
+func TestRuleSetUncompiled(t *testing.T) {
+	message := &OSCMessage{Address: "/a", Args: []OSCArg{}}
+	expected := &OSCMessage{Address: "/b", Args: []OSCArg{}}
+
+	literal := &RuleSet{Rules: []*Rule{{Match: "/a", Address: "/b"}}}
+	got, err := literal.Apply(message)
+	if err != nil {
+		t.Fatalf("failed to apply rules: %s", err.Error())
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("failed to rewrite message got '%v', expected '%v'", got, expected)
+	}
+
+	unmarshaled := &RuleSet{}
+	err = json.Unmarshal([]byte(`{"rules": [{"match": "/a", "address": "/b"}]}`), unmarshaled)
+	if err != nil {
+		t.Fatalf("failed to decode rules: %s", err.Error())
+	}
+	got, err = unmarshaled.Apply(message)
+	if err != nil {
+		t.Fatalf("failed to apply rules: %s", err.Error())
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("failed to rewrite message got '%v', expected '%v'", got, expected)
+	}
+
+	bad := &RuleSet{Rules: []*Rule{{Match: "a"}}}
+	_, err = bad.Apply(message)
+	if err == nil {
+		t.Fatalf("expected uncompiled bad rules to fail")
+	}
+	if err.Error() != "OSC rule 1: OSC address pattern must start with /" {
+		t.Fatalf("failed to reject rules got '%s', expected '%s'", err.Error(), "OSC rule 1: OSC address pattern must start with /")
+	}
+}
+
+func TestBadRuleSet(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		rules    string
+		errorMsg string
+	}{
+		{
+			name:     "bad match",
+			rules:    `{"rules": [{"match": "ch"}]}`,
+			errorMsg: "OSC rule 1: OSC address pattern must start with /",
+		},
+		{
+			name:     "bad address",
+			rules:    `{"rules": [{"match": "/ch", "address": "ch"}]}`,
+			errorMsg: "OSC rule 1: OSC rule address must start with /",
+		},
+		{
+			name:     "address capture out of range",
+			rules:    `{"rules": [{"match": "/ch/*", "address": "/{2}"}]}`,
+			errorMsg: "OSC rule 1: OSC rule address refers to capture 2 but match has 1",
+		},
+		{
+			name:     "arg without a source",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"type": "i"}]}]}`,
+			errorMsg: "OSC rule 1: OSC rule arg must set exactly one of arg, capture or value",
+		},
+		{
+			name:     "arg capture out of range",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"capture": 1}]}]}`,
+			errorMsg: "OSC rule 1: OSC rule arg refers to capture 1 but match has 0",
+		},
+		{
+			name:     "empty scale",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"arg": 0, "scale": {"in": [1, 1], "out": [0, 1]}}]}]}`,
+			errorMsg: "OSC rule 1: OSC rule scale in range must not be empty",
+		},
+		{
+			name:     "log scale through zero",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"arg": 0, "scale": {"in": [0, 1], "out": [0, 1], "curve": "log"}}]}]}`,
+			errorMsg: "OSC rule 1: OSC rule log scale out range must be positive",
+		},
+		{
+			name:     "unknown curve",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"arg": 0, "scale": {"in": [0, 1], "out": [0, 1], "curve": "cubic"}}]}]}`,
+			errorMsg: "OSC rule 1: unsupported OSC rule scale curve: cubic",
+		},
+		{
+			name:     "bad clamp",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"arg": 0, "clamp": [1, 0]}]}]}`,
+			errorMsg: "OSC rule 1: OSC rule clamp must be [min, max]",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := RuleSetFromJSON([]byte(testCase.rules))
+			if err == nil {
+				t.Fatalf("expected rules to fail")
+			}
+			if err.Error() != testCase.errorMsg {
+				t.Fatalf("failed to reject rules got '%s', expected '%s'", err.Error(), testCase.errorMsg)
+			}
+		})
+	}
+}
+
+func TestRuleSetApplyErrors(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		rules    string
+		message  *OSCMessage
+		errorMsg string
+	}{
+		{
+			name:     "missing arg",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"arg": 1}]}]}`,
+			message:  &OSCMessage{Address: "/ch", Args: []OSCArg{{Type: "i", Value: int32(1)}}},
+			errorMsg: "OSC rule arg 1 is out of range for message with 1 args",
+		},
+		{
+			name:     "scale a string",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"arg": 0, "scale": {"in": [0, 1], "out": [0, 127]}}]}]}`,
+			message:  &OSCMessage{Address: "/ch", Args: []OSCArg{{Type: "s", Value: "loud"}}},
+			errorMsg: "OSC arg value \"loud\" is not a number",
+		},
+		{
+			name:     "convert a blob",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"arg": 0, "type": "i"}]}]}`,
+			message:  &OSCMessage{Address: "/ch", Args: []OSCArg{{Type: "b", Value: []byte{1}}}},
+			errorMsg: "OSC arg with type b is not a number",
+		},
+		{
+			name:     "int64 out of range",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"arg": 0, "type": "h", "scale": {"in": [0, 1], "out": [0, 1e19]}}]}]}`,
+			message:  &OSCMessage{Address: "/ch", Args: []OSCArg{{Type: "f", Value: float32(1)}}},
+			errorMsg: "OSC arg value 1e+19 does not fit in an int64",
+		},
+		{
+			name:     "NaN to int64",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"arg": 0, "type": "h"}]}]}`,
+			message:  &OSCMessage{Address: "/ch", Args: []OSCArg{{Type: "s", Value: "NaN"}}},
+			errorMsg: "OSC arg value NaN does not fit in an int64",
+		},
+		{
+			name:     "NaN to int32",
+			rules:    `{"rules": [{"match": "/ch", "args": [{"arg": 0, "type": "i"}]}]}`,
+			message:  &OSCMessage{Address: "/ch", Args: []OSCArg{{Type: "s", Value: "NaN"}}},
+			errorMsg: "OSC arg value NaN does not fit in an int32",
+		},
+		{
+			name:     "capture is not a number",
+			rules:    `{"rules": [{"match": "/ch/*", "address": "/mixer/{1:i}"}]}`,
+			message:  &OSCMessage{Address: "/ch/main", Args: []OSCArg{}},
+			errorMsg: "OSC arg value \"main\" is not a number",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ruleSet, err := RuleSetFromJSON([]byte(testCase.rules))
+			if err != nil {
+				t.Fatalf("failed to load rules: %s", err.Error())
+			}
+
+			_, err = ruleSet.Apply(testCase.message)
+			if err == nil {
+				t.Fatalf("expected rules to fail")
+			}
+			if err.Error() != testCase.errorMsg {
+				t.Fatalf("failed to reject message got '%s', expected '%s'", err.Error(), testCase.errorMsg)
+			}
+		})
+	}
+}
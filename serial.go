@@ -0,0 +1,21 @@
+package osc
+
+// SerialAddr is the address of a serial device like /dev/ttyACM0
+type SerialAddr string
+
+func (a SerialAddr) Network() string {
+	return "serial"
+}
+
+func (a SerialAddr) String() string {
+	return string(a)
+}
+
+// OpenSerial opens a serial device in raw mode at the given baud rate and exchanges SLIP framed OSC packets over it
+func OpenSerial(device string, baud int) (*StreamConn, error) {
+	port, err := openSerialPort(device, baud)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamConn(port, SLIPFraming, SerialAddr(device), SerialAddr(device)), nil
+}
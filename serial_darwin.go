@@ -0,0 +1,40 @@
+//go:build darwin
+
+package osc
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func openSerialPort(device string, baud int) (*os.File, error) {
+	fd, err := unix.Open(device, unix.O_RDWR|unix.O_NOCTTY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: device, Err: err}
+	}
+
+	termios, err := unix.IoctlGetTermios(fd, unix.TIOCGETA)
+	if err != nil {
+		unix.Close(fd)
+		return nil, &os.PathError{Op: "get termios", Path: device, Err: err}
+	}
+
+	// NOTE: equivalent of cfmakeraw, OSC is binary so nothing can be translated
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Oflag &^= unix.OPOST
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8 | unix.CREAD | unix.CLOCAL
+	termios.Ispeed = uint64(baud)
+	termios.Ospeed = uint64(baud)
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+
+	if err := unix.IoctlSetTermios(fd, unix.TIOCSETA, termios); err != nil {
+		unix.Close(fd)
+		return nil, &os.PathError{Op: "set termios", Path: device, Err: err}
+	}
+
+	return os.NewFile(uintptr(fd), device), nil
+}
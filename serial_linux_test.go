@@ -0,0 +1,86 @@
+//go:build linux
+
+package osc
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func openPTY(t *testing.T) (*os.File, string) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pseudo terminals are not available: %s", err.Error())
+	}
+	t.Cleanup(func() { master.Close() })
+
+	if err := unix.IoctlSetPointerInt(int(master.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+		t.Fatalf("failed to unlock pty: %s", err.Error())
+	}
+	number, err := unix.IoctlGetInt(int(master.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		t.Fatalf("failed to get pty number: %s", err.Error())
+	}
+	return master, fmt.Sprintf("/dev/pts/%d", number)
+}
+
+func TestSerialRoundTrip(t *testing.T) {
+	master, device := openPTY(t)
+
+	conn, err := OpenSerial(device, 115200)
+	if err != nil {
+		t.Fatalf("failed to open serial device: %s", err.Error())
+	}
+	defer conn.Close()
+
+	if conn.RemoteAddr().String() != device {
+		t.Fatalf("failed to set remote address got '%s', expected '%s'", conn.RemoteAddr(), device)
+	}
+
+	message := &OSCMessage{Address: "/serial", Args: []OSCArg{{Type: "b", Value: []byte{0xc0, 0xdb, '\r', '\n'}}}}
+	messageBytes, err := message.ToBytes()
+	if err != nil {
+		t.Fatalf("failed to encode message: %s", err.Error())
+	}
+
+	if _, err := master.Write(SLIPEncode(messageBytes)); err != nil {
+		t.Fatalf("failed to write to pty: %s", err.Error())
+	}
+	got, source, err := conn.ReadPacket()
+	if err != nil {
+		t.Fatalf("failed to read packet: %s", err.Error())
+	}
+	if !reflect.DeepEqual(got, message) {
+		t.Fatalf("failed to receive packet got '%v', expected '%v'", got, message)
+	}
+	if source.Network() != "serial" {
+		t.Fatalf("failed to set source network got '%s', expected 'serial'", source.Network())
+	}
+
+	if err := conn.WritePacket(message); err != nil {
+		t.Fatalf("failed to write packet: %s", err.Error())
+	}
+	frame, err := readSLIPFrame(bufio.NewReader(master))
+	if err != nil {
+		t.Fatalf("failed to read frame from pty: %s", err.Error())
+	}
+	if !reflect.DeepEqual(frame, messageBytes) {
+		t.Fatalf("failed to write frame got '%v', expected '%v'", frame, messageBytes)
+	}
+}
+
+func TestSerialUnsupportedBaud(t *testing.T) {
+	_, err := OpenSerial("/dev/null", 1234)
+	if err == nil {
+		t.Fatalf("expected an error for an unsupported baud rate")
+	}
+	if err.Error() != "unsupported baud rate: 1234" {
+		t.Fatalf("failed to report baud rate got '%s', expected '%s'", err.Error(), "unsupported baud rate: 1234")
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package osc
+
+import (
+	"errors"
+	"os"
+)
+
+func openSerialPort(device string, baud int) (*os.File, error) {
+	return nil, errors.New("serial ports are not supported on this platform")
+}
@@ -0,0 +1,195 @@
+package osc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// PacketWriter sends OSC packets back to wherever a received packet came from
+type PacketWriter interface {
+	WritePacket(packet OSCPacket) error
+}
+
+type Handler interface {
+	ServeOSC(w PacketWriter, packet OSCPacket, source net.Addr)
+}
+
+type HandlerFunc func(w PacketWriter, packet OSCPacket, source net.Addr)
+
+func (f HandlerFunc) ServeOSC(w PacketWriter, packet OSCPacket, source net.Addr) {
+	f(w, packet, source)
+}
+
+type Server struct {
+	Handler Handler
+	// Framing is used for stream networks like tcp and unix
+	Framing Framing
+	// ErrorHandler is called with malformed packets and errors that don't stop the server, nil ignores them
+	ErrorHandler func(err error, source net.Addr)
+
+	// NOTE: keyed by id since a Conn doesn't have to be comparable
+	closers map[int]io.Closer
+	nextID  int
+	closed  bool
+	mutex   sync.Mutex
+}
+
+// Close stops every listener, connection and WebSocket server being served, the Serve methods then return nil
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	s.closed = true
+	closers := s.closers
+	s.closers = nil
+	s.mutex.Unlock()
+
+	errs := []error{}
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// track remembers closer for Close, it closes closer and returns false if the Server is already closed
+func (s *Server) track(closer io.Closer) (int, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		closer.Close()
+		return 0, false
+	}
+	if s.closers == nil {
+		s.closers = map[int]io.Closer{}
+	}
+	s.nextID++
+	s.closers[s.nextID] = closer
+	return s.nextID, true
+}
+
+func (s *Server) untrack(id int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.closers, id)
+}
+
+func (s *Server) ListenAndServe(network string, address string) error {
+	switch network {
+	case "udp", "udp4", "udp6":
+		conn, err := ListenUDP(network, address)
+		if err != nil {
+			return err
+		}
+		return s.Serve(conn)
+	case "unixgram":
+		conn, err := ListenUnixgram(address)
+		if err != nil {
+			return err
+		}
+		return s.Serve(conn)
+	case "tcp", "tcp4", "tcp6", "unix":
+		listener, err := ListenStream(network, address, s.Framing)
+		if err != nil {
+			return err
+		}
+		return s.ServeStream(listener)
+	case "ws":
+		httpServer := &http.Server{Addr: address, Handler: s.WebSocketHandler()}
+		id, ok := s.track(httpServer)
+		if !ok {
+			return nil
+		}
+		defer s.untrack(id)
+		// NOTE: WebSocket connections are hijacked from the http.Server so Serve tracks each of them too
+		if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported network: %s", network)
+	}
+}
+
+// Serve reads packets from conn and passes them to the Handler until conn is closed
+func (s *Server) Serve(conn Conn) error {
+	defer conn.Close()
+	id, ok := s.track(conn)
+	if !ok {
+		return nil
+	}
+	defer s.untrack(id)
+
+	for {
+		packet, source, err := conn.ReadPacket()
+		if err != nil {
+			var decodeErr *DecodeError
+			if errors.As(err, &decodeErr) {
+				s.reportError(err, source)
+				continue
+			}
+			if errors.Is(err, net.ErrClosed) || errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		s.Handler.ServeOSC(replyWriter(conn, source), packet, source)
+	}
+}
+
+// ServeStream accepts connections from listener and serves each one until the listener is closed
+func (s *Server) ServeStream(listener *StreamListener) error {
+	defer listener.Close()
+	id, ok := s.track(listener)
+	if !ok {
+		return nil
+	}
+	defer s.untrack(id)
+
+	for {
+		conn, err := listener.AcceptStream()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		go func() {
+			if err := s.Serve(conn); err != nil {
+				s.reportError(err, conn.RemoteAddr())
+			}
+		}()
+	}
+}
+
+func (s *Server) reportError(err error, source net.Addr) {
+	if s.ErrorHandler != nil {
+		s.ErrorHandler(err, source)
+	}
+}
+
+type packetConnWriter struct {
+	conn PacketConn
+	addr net.Addr
+}
+
+func (w packetConnWriter) WritePacket(packet OSCPacket) error {
+	return w.conn.WritePacketTo(packet, w.addr)
+}
+
+func replyWriter(conn Conn, source net.Addr) PacketWriter {
+	// NOTE: connected sockets can only write to the address they are connected to
+	if connected, ok := conn.(interface{ RemoteAddr() net.Addr }); ok && connected.RemoteAddr() != nil {
+		return conn
+	}
+	if packetConn, ok := conn.(PacketConn); ok && source != nil {
+		return packetConnWriter{conn: packetConn, addr: source}
+	}
+	return conn
+}
@@ -0,0 +1,272 @@
+package osc
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func echoHandler(t *testing.T) Handler {
+	return HandlerFunc(func(w PacketWriter, packet OSCPacket, source net.Addr) {
+		if err := w.WritePacket(packet); err != nil {
+			t.Errorf("failed to echo packet: %s", err.Error())
+		}
+	})
+}
+
+func TestServerStreamEcho(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		network string
+		framing Framing
+	}{
+		{name: "tcp size framing", network: "tcp", framing: SizeFraming},
+		{name: "tcp slip framing", network: "tcp", framing: SLIPFraming},
+		{name: "unix size framing", network: "unix", framing: SizeFraming},
+		{name: "unix slip framing", network: "unix", framing: SLIPFraming},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			address := "127.0.0.1:0"
+			if testCase.network == "unix" {
+				address = filepath.Join(t.TempDir(), "osc.sock")
+			}
+
+			listener, err := ListenStream(testCase.network, address, testCase.framing)
+			if err != nil {
+				t.Fatalf("failed to listen: %s", err.Error())
+			}
+
+			server := &Server{Handler: echoHandler(t)}
+			go server.ServeStream(listener)
+			defer listener.Close()
+
+			client, err := Dial(testCase.network, listener.Addr().String(), testCase.framing)
+			if err != nil {
+				t.Fatalf("failed to dial: %s", err.Error())
+			}
+			defer client.Close()
+
+			message := &OSCMessage{Address: "/echo", Args: []OSCArg{{Type: "s", Value: "hello"}}}
+			err = client.WritePacket(message)
+			if err != nil {
+				t.Fatalf("failed to write packet: %s", err.Error())
+			}
+
+			got, _, err := client.ReadPacket()
+			if err != nil {
+				t.Fatalf("failed to read echo: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(got, message) {
+				t.Fatalf("failed to echo packet got '%v', expected '%v'", got, message)
+			}
+		})
+	}
+}
+
+func TestServerDatagramEcho(t *testing.T) {
+	socketDir := t.TempDir()
+
+	testCases := []struct {
+		name   string
+		listen func() (PacketConn, error)
+		dial   func(addr net.Addr) (Conn, error)
+	}{
+		{
+			name: "udp",
+			listen: func() (PacketConn, error) {
+				return ListenUDP("udp4", "127.0.0.1:0")
+			},
+			dial: func(addr net.Addr) (Conn, error) {
+				return DialUDP("udp4", addr.String())
+			},
+		},
+		{
+			name: "unixgram",
+			listen: func() (PacketConn, error) {
+				return ListenUnixgram(filepath.Join(socketDir, "server.sock"))
+			},
+			dial: func(addr net.Addr) (Conn, error) {
+				return DialUnixgram(filepath.Join(socketDir, "client.sock"), addr.String())
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			conn, err := testCase.listen()
+			if err != nil {
+				t.Fatalf("failed to listen: %s", err.Error())
+			}
+
+			server := &Server{Handler: echoHandler(t)}
+			go server.Serve(conn)
+			defer conn.Close()
+
+			client, err := testCase.dial(conn.(interface{ LocalAddr() net.Addr }).LocalAddr())
+			if err != nil {
+				t.Fatalf("failed to dial: %s", err.Error())
+			}
+			defer client.Close()
+
+			message := &OSCMessage{Address: "/echo", Args: []OSCArg{{Type: "i", Value: int32(7)}}}
+			err = client.WritePacket(message)
+			if err != nil {
+				t.Fatalf("failed to write packet: %s", err.Error())
+			}
+
+			client.(interface{ SetReadDeadline(time.Time) error }).SetReadDeadline(time.Now().Add(time.Second))
+			got, _, err := client.ReadPacket()
+			if err != nil {
+				t.Fatalf("failed to read echo: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(got, message) {
+				t.Fatalf("failed to echo packet got '%v', expected '%v'", got, message)
+			}
+		})
+	}
+}
+
+func TestServerErrorHandler(t *testing.T) {
+	conn, err := ListenUDP("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer conn.Close()
+
+	errs := make(chan error, 1)
+	server := &Server{
+		Handler: echoHandler(t),
+		ErrorHandler: func(err error, source net.Addr) {
+			errs <- err
+		},
+	}
+	go server.Serve(conn)
+
+	client, err := net.Dial("udp4", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	defer client.Close()
+
+	client.Write([]byte("junk"))
+
+	select {
+	case err := <-errs:
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Fatalf("expected a DecodeError but got: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("ErrorHandler was not called for a malformed packet")
+	}
+}
+
+func TestServerClose(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		network string
+	}{
+		{name: "udp", network: "udp"},
+		{name: "tcp", network: "tcp"},
+		{name: "unixgram", network: "unixgram"},
+		{name: "websocket", network: "ws"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			address := "127.0.0.1:0"
+			if testCase.network == "unixgram" {
+				address = filepath.Join(t.TempDir(), "osc.sock")
+			}
+
+			server := &Server{Handler: echoHandler(t)}
+			done := make(chan error)
+			go func() {
+				done <- server.ListenAndServe(testCase.network, address)
+			}()
+
+			// NOTE: wait for the listener to be tracked so Close has something to stop
+			deadline := time.Now().Add(time.Second)
+			for {
+				server.mutex.Lock()
+				listening := len(server.closers) > 0
+				server.mutex.Unlock()
+				if listening {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("failed to start listening")
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			err := server.Close()
+			if err != nil {
+				t.Fatalf("failed to close server: %s", err.Error())
+			}
+
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("failed to stop serving cleanly: %s", err.Error())
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("failed to stop serving after close")
+			}
+		})
+	}
+}
+
+func TestServerCloseConnections(t *testing.T) {
+	listener, err := ListenStream("tcp", "127.0.0.1:0", SizeFraming)
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+
+	server := &Server{Handler: echoHandler(t)}
+	done := make(chan error)
+	go func() {
+		done <- server.ServeStream(listener)
+	}()
+
+	client, err := Dial("tcp", listener.Addr().String(), SizeFraming)
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	defer client.Close()
+
+	message := &OSCMessage{Address: "/echo", Args: []OSCArg{}}
+	err = client.WritePacket(message)
+	if err != nil {
+		t.Fatalf("failed to write packet: %s", err.Error())
+	}
+	_, _, err = client.ReadPacket()
+	if err != nil {
+		t.Fatalf("failed to read echo: %s", err.Error())
+	}
+
+	err = server.Close()
+	if err != nil {
+		t.Fatalf("failed to close server: %s", err.Error())
+	}
+	<-done
+
+	_, _, err = client.ReadPacket()
+	if err == nil {
+		t.Fatalf("expected the served connection to be closed")
+	}
+
+	err = server.Serve(client)
+	if err != nil {
+		t.Fatalf("failed to refuse serving after close: %s", err.Error())
+	}
+}
@@ -0,0 +1,269 @@
+package osc
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// SignatureAddress is the address of the message a Signer puts after each signed packet
+const SignatureAddress = "/signature"
+
+// DefaultSignatureMaxAge is how far a signature's time can be from the receiver's clock by default
+const DefaultSignatureMaxAge = 5 * time.Second
+
+// SignatureOverhead is how many bytes signing adds to a packet: the bundle header, two element sizes and the signature message
+const SignatureOverhead = bundleHeaderSize + 4 + 4 + signatureMessageSize
+
+// signatureMessageSize is the padded address, type tags, time tag, nonce blob and signature blob
+const signatureMessageSize = 12 + 8 + 8 + (4 + signatureNonceSize) + (4 + sha256.Size)
+
+const signatureNonceSize = 16
+
+// Signer is a PacketWriter that signs packets with HMAC-SHA256 before writing them to another PacketWriter
+//
+// A signed packet is an immediate bundle holding the packet followed by a SignatureAddress message with a time tag of
+// when it was signed, a random nonce blob and a blob of the HMAC-SHA256 of the packet bytes, time tag and nonce.
+type Signer struct {
+	writer PacketWriter
+	key    []byte
+	now    func() time.Time
+}
+
+func NewSigner(writer PacketWriter, key []byte) *Signer {
+	return &Signer{
+		writer: writer,
+		key:    key,
+		now:    time.Now,
+	}
+}
+
+// Sign wraps packet in a bundle with its signature
+func (s *Signer) Sign(packet OSCPacket) (*OSCBundle, error) {
+	packetBytes, err := packet.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, signatureNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	timeTag := TimeTagFromTime(s.now())
+
+	return &OSCBundle{
+		TimeTag: ImmediateTimeTag(),
+		Contents: []OSCPacket{
+			packet,
+			&OSCMessage{
+				Address: SignatureAddress,
+				Args: []OSCArg{
+					{Type: "t", Value: timeTag},
+					{Type: "b", Value: nonce},
+					{Type: "b", Value: signature(s.key, packetBytes, timeTag, nonce)},
+				},
+			},
+		},
+	}, nil
+}
+
+func (s *Signer) WritePacket(packet OSCPacket) error {
+	signed, err := s.Sign(packet)
+	if err != nil {
+		return err
+	}
+	return s.writer.WritePacket(signed)
+}
+
+// Verifier is a Handler that checks signed packets and drops unsigned messages to addresses that must be signed
+//
+// A signed packet is only passed on if the signature matches, its time is within MaxAge of now and its nonce hasn't
+// been seen before, the signature bundle is removed so Handler gets the packet as it was sent.
+type Verifier struct {
+	Handler Handler
+	// MaxAge is how far a signature's time can be from now, nonces are remembered for this long to reject replays
+	MaxAge time.Duration
+	// OnReject is called with each packet or message rejected and why, nil ignores them
+	OnReject func(packet OSCPacket, source net.Addr, err error)
+
+	key       []byte
+	patterns  []*AddressPattern
+	nonces    map[string]time.Time
+	lastPrune time.Time
+	rejected  int
+	now       func() time.Time
+	mutex     sync.Mutex
+}
+
+// NewVerifier checks packets signed with key, every message must be signed until Require narrows it down
+func NewVerifier(handler Handler, key []byte) *Verifier {
+	return &Verifier{
+		Handler:  handler,
+		MaxAge:   DefaultSignatureMaxAge,
+		key:      key,
+		patterns: []*AddressPattern{},
+		nonces:   map[string]time.Time{},
+		now:      time.Now,
+	}
+}
+
+// Require makes messages with an address matching pattern need a signature, others can be sent unsigned
+func (v *Verifier) Require(pattern string) error {
+	addressPattern, err := CompileAddressPattern(pattern)
+	if err != nil {
+		return err
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.patterns = append(v.patterns, addressPattern)
+	return nil
+}
+
+// Required reports whether a message to address must be signed
+func (v *Verifier) Required(address string) bool {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if len(v.patterns) == 0 {
+		return true
+	}
+	for _, pattern := range v.patterns {
+		if pattern.Match(address) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rejected is the number of packets and messages dropped so far
+func (v *Verifier) Rejected() int {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	return v.rejected
+}
+
+func (v *Verifier) ServeOSC(w PacketWriter, packet OSCPacket, source net.Addr) {
+	if allowed := v.prune(packet, source); allowed != nil {
+		v.Handler.ServeOSC(w, allowed, source)
+	}
+}
+
+// prune returns the packet with signatures removed and rejected packets and messages removed or nil if nothing is left
+func (v *Verifier) prune(packet OSCPacket, source net.Addr) OSCPacket {
+	return rebuildPacket(packet, func(packet OSCPacket) (OSCPacket, bool) {
+		switch packet := packet.(type) {
+		case *OSCMessage:
+			if v.Required(packet.Address) {
+				v.reject(packet, source, fmt.Errorf("OSC message %s must be signed", packet.Address))
+				return nil, true
+			}
+			return packet, true
+		case *OSCBundle:
+			if signatureMessage(packet) == nil {
+				return nil, false
+			}
+			signed, err := v.verify(packet)
+			if err != nil {
+				v.reject(packet, source, err)
+				return nil, true
+			}
+			return signed, true
+		}
+		return nil, false
+	})
+}
+
+// verify checks the signature of a signed bundle and returns the packet it signed
+func (v *Verifier) verify(bundle *OSCBundle) (OSCPacket, error) {
+	message := signatureMessage(bundle)
+	if len(message.Args) != 3 || message.Args[0].Type != "t" || message.Args[1].Type != "b" || message.Args[2].Type != "b" {
+		return nil, errors.New("OSC signature must have a time tag, nonce blob and signature blob")
+	}
+	timeTag, ok := message.Args[0].Value.(OSCTimeTag)
+	if !ok {
+		return nil, errors.New("OSC signature must have a time tag, nonce blob and signature blob")
+	}
+	nonce, ok := message.Args[1].Value.([]byte)
+	if !ok || len(nonce) != signatureNonceSize {
+		return nil, fmt.Errorf("OSC signature nonce must be %d bytes", signatureNonceSize)
+	}
+	mac, ok := message.Args[2].Value.([]byte)
+	if !ok {
+		return nil, errors.New("OSC signature must have a time tag, nonce blob and signature blob")
+	}
+
+	// NOTE: the packet is re-encoded to check it, which gives back the bytes it was decoded from
+	packet := bundle.Contents[0]
+	packetBytes, err := packet.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(mac, signature(v.key, packetBytes, timeTag, nonce)) {
+		return nil, errors.New("OSC signature does not match")
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	now := v.now()
+	signedAt := timeTag.Time()
+	if signedAt.Before(now.Add(-v.MaxAge)) || signedAt.After(now.Add(v.MaxAge)) {
+		return nil, fmt.Errorf("OSC signature time %s is more than %s from now", signedAt.Format(time.RFC3339Nano), v.MaxAge)
+	}
+
+	v.pruneNonces(now)
+	if _, ok := v.nonces[string(nonce)]; ok {
+		return nil, errors.New("OSC signature nonce has already been used")
+	}
+	// NOTE: once the signature time is over MaxAge old it is rejected anyway so the nonce can be forgotten
+	v.nonces[string(nonce)] = signedAt.Add(v.MaxAge)
+	return packet, nil
+}
+
+func (v *Verifier) pruneNonces(now time.Time) {
+	if now.Sub(v.lastPrune) < v.MaxAge {
+		return
+	}
+	v.lastPrune = now
+	for nonce, expires := range v.nonces {
+		if now.After(expires) {
+			delete(v.nonces, nonce)
+		}
+	}
+}
+
+func (v *Verifier) reject(packet OSCPacket, source net.Addr, err error) {
+	v.mutex.Lock()
+	v.rejected++
+	v.mutex.Unlock()
+
+	if v.OnReject != nil {
+		v.OnReject(packet, source, err)
+	}
+}
+
+// signatureMessage returns the signature of a bundle written by a Signer or nil if bundle isn't one
+func signatureMessage(bundle *OSCBundle) *OSCMessage {
+	if len(bundle.Contents) != 2 {
+		return nil
+	}
+	message, ok := bundle.Contents[1].(*OSCMessage)
+	if !ok || message.Address != SignatureAddress {
+		return nil
+	}
+	return message
+}
+
+func signature(key []byte, packetBytes []byte, timeTag OSCTimeTag, nonce []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(packetBytes)
+	mac.Write(timeTagToOSCBytes(timeTag))
+	mac.Write(nonce)
+	return mac.Sum(nil)
+}
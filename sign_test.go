@@ -0,0 +1,222 @@
+package osc
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVerifier(t *testing.T) {
+	key := []byte("shared secret")
+	cue := &OSCMessage{Address: "/cue/go", Args: []OSCArg{{Type: "i", Value: int32(12)}}}
+	meter := &OSCMessage{Address: "/meter/1", Args: []OSCArg{{Type: "f", Value: float32(0.5)}}}
+	cueBundle := &OSCBundle{TimeTag: ImmediateTimeTag(), Contents: []OSCPacket{cue, meter}}
+
+	type send struct {
+		// NOTE: signedBy nil sends the packet unsigned
+		signedBy []byte
+		signedAt time.Duration
+		packet   OSCPacket
+		replay   bool
+		expected OSCPacket
+	}
+
+	testCases := []struct {
+		name     string
+		required []string
+		sends    []send
+		rejected int
+	}{
+		{
+			name:     "signed message",
+			sends:    []send{{signedBy: key, packet: cue, expected: cue}},
+			rejected: 0,
+		},
+		{
+			name:     "signed bundle",
+			sends:    []send{{signedBy: key, packet: cueBundle, expected: cueBundle}},
+			rejected: 0,
+		},
+		{
+			name:     "unsigned message",
+			sends:    []send{{packet: cue, expected: nil}},
+			rejected: 1,
+		},
+		{
+			name:     "wrong key",
+			sends:    []send{{signedBy: []byte("guess"), packet: cue, expected: nil}},
+			rejected: 1,
+		},
+		{
+			name: "replay",
+			sends: []send{
+				{signedBy: key, packet: cue, expected: cue},
+				{signedBy: key, packet: cue, replay: true, expected: nil},
+			},
+			rejected: 1,
+		},
+		{
+			name:     "too old",
+			sends:    []send{{signedBy: key, signedAt: -10 * time.Second, packet: cue, expected: nil}},
+			rejected: 1,
+		},
+		{
+			name:     "from the future",
+			sends:    []send{{signedBy: key, signedAt: 10 * time.Second, packet: cue, expected: nil}},
+			rejected: 1,
+		},
+		{
+			name:     "unsigned message to an address that doesn't require signing",
+			required: []string{"/cue/*"},
+			sends:    []send{{packet: meter, expected: meter}},
+			rejected: 0,
+		},
+		{
+			name:     "unsigned bundle is pruned to addresses that don't require signing",
+			required: []string{"/cue/*"},
+			sends:    []send{{packet: cueBundle, expected: &OSCBundle{TimeTag: ImmediateTimeTag(), Contents: []OSCPacket{meter}}}},
+			rejected: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+			var got OSCPacket
+			verifier := NewVerifier(HandlerFunc(func(w PacketWriter, packet OSCPacket, source net.Addr) {
+				got = packet
+			}), key)
+			verifier.now = clock.Now
+			for _, pattern := range testCase.required {
+				err := verifier.Require(pattern)
+				if err != nil {
+					t.Fatalf("failed to require pattern: %s", err.Error())
+				}
+			}
+
+			var lastBytes []byte
+			for index, send := range testCase.sends {
+				packetBytes := lastBytes
+				if !send.replay {
+					packet := send.packet
+					if send.signedBy != nil {
+						signer := NewSigner(nil, send.signedBy)
+						signer.now = func() time.Time { return clock.now.Add(send.signedAt) }
+						signed, err := signer.Sign(send.packet)
+						if err != nil {
+							t.Fatalf("failed to sign: %s", err.Error())
+						}
+						packet = signed
+					}
+					var err error
+					packetBytes, err = packet.ToBytes()
+					if err != nil {
+						t.Fatalf("failed to encode: %s", err.Error())
+					}
+				}
+				lastBytes = packetBytes
+
+				// NOTE: go through bytes so the verifier sees packets the way a server hands them over
+				received, _, err := PacketFromBytes(packetBytes)
+				if err != nil {
+					t.Fatalf("failed to decode: %s", err.Error())
+				}
+				got = nil
+				verifier.ServeOSC(nil, received, nil)
+				if !reflect.DeepEqual(got, testCase.sends[index].expected) {
+					t.Fatalf("failed to verify send %d got '%v', expected '%v'", index, got, send.expected)
+				}
+			}
+
+			if verifier.Rejected() != testCase.rejected {
+				t.Fatalf("failed to count rejected got %d, expected %d", verifier.Rejected(), testCase.rejected)
+			}
+		})
+	}
+}
+
+func TestVerifierRejectReasons(t *testing.T) {
+	key := []byte("shared secret")
+	cue := &OSCMessage{Address: "/cue/go", Args: []OSCArg{{Type: "i", Value: int32(12)}}}
+
+	signed, err := NewSigner(nil, key).Sign(cue)
+	if err != nil {
+		t.Fatalf("failed to sign: %s", err.Error())
+	}
+	tampered := &OSCBundle{TimeTag: signed.TimeTag, Contents: []OSCPacket{
+		&OSCMessage{Address: "/cue/go", Args: []OSCArg{{Type: "i", Value: int32(13)}}},
+		signed.Contents[1],
+	}}
+	shortNonce := &OSCBundle{TimeTag: signed.TimeTag, Contents: []OSCPacket{
+		cue,
+		&OSCMessage{Address: SignatureAddress, Args: []OSCArg{
+			signed.Contents[1].(*OSCMessage).Args[0],
+			{Type: "b", Value: []byte{1, 2, 3}},
+			signed.Contents[1].(*OSCMessage).Args[2],
+		}},
+	}}
+	missingArgs := &OSCBundle{TimeTag: signed.TimeTag, Contents: []OSCPacket{
+		cue,
+		&OSCMessage{Address: SignatureAddress, Args: []OSCArg{}},
+	}}
+
+	testCases := []struct {
+		name     string
+		packet   OSCPacket
+		errorMsg string
+	}{
+		{name: "unsigned", packet: cue, errorMsg: "OSC message /cue/go must be signed"},
+		{name: "tampered", packet: tampered, errorMsg: "OSC signature does not match"},
+		{name: "short nonce", packet: shortNonce, errorMsg: "OSC signature nonce must be 16 bytes"},
+		{name: "missing args", packet: missingArgs, errorMsg: "OSC signature must have a time tag, nonce blob and signature blob"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var rejectErr error
+			verifier := NewVerifier(HandlerFunc(func(w PacketWriter, packet OSCPacket, source net.Addr) {}), key)
+			verifier.OnReject = func(packet OSCPacket, source net.Addr, err error) {
+				rejectErr = err
+			}
+			verifier.ServeOSC(nil, testCase.packet, nil)
+			if rejectErr == nil {
+				t.Fatalf("expected packet to be rejected")
+			}
+			if rejectErr.Error() != testCase.errorMsg {
+				t.Fatalf("failed to reject packet got '%s', expected '%s'", rejectErr.Error(), testCase.errorMsg)
+			}
+		})
+	}
+}
+
+func TestSignerWritePacket(t *testing.T) {
+	writer := newRecordingWriter()
+	cue := &OSCMessage{Address: "/cue/go", Args: []OSCArg{}}
+
+	err := NewSigner(writer, []byte("shared secret")).WritePacket(cue)
+	if err != nil {
+		t.Fatalf("failed to write signed packet: %s", err.Error())
+	}
+
+	packets := writer.Packets()
+	if len(packets) != 1 {
+		t.Fatalf("failed to write signed packet got %d packets, expected 1", len(packets))
+	}
+	bundle, ok := packets[0].(*OSCBundle)
+	if !ok || signatureMessage(bundle) == nil || bundle.Contents[0] != cue {
+		t.Fatalf("failed to write signed packet got '%v'", packets[0])
+	}
+
+	cueBytes, err := cue.ToBytes()
+	if err != nil {
+		t.Fatalf("failed to encode: %s", err.Error())
+	}
+	signedBytes, err := bundle.ToBytes()
+	if err != nil {
+		t.Fatalf("failed to encode: %s", err.Error())
+	}
+	if len(signedBytes)-len(cueBytes) != SignatureOverhead {
+		t.Fatalf("failed to match signature overhead got %d, expected %d", len(signedBytes)-len(cueBytes), SignatureOverhead)
+	}
+}
@@ -0,0 +1,109 @@
+package osc
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+)
+
+const (
+	slipEnd    = byte(0xc0)
+	slipEsc    = byte(0xdb)
+	slipEscEnd = byte(0xdc)
+	slipEscEsc = byte(0xdd)
+)
+
+// SLIPEncode frames bytes as described in RFC 1055 with a leading and trailing END byte
+func SLIPEncode(bytes []byte) []byte {
+	var encodedBytes = []byte{slipEnd}
+
+	for _, byteToEncode := range bytes {
+		switch byteToEncode {
+		case slipEnd:
+			encodedBytes = append(encodedBytes, slipEsc, slipEscEnd)
+		case slipEsc:
+			encodedBytes = append(encodedBytes, slipEsc, slipEscEsc)
+		default:
+			encodedBytes = append(encodedBytes, byteToEncode)
+		}
+	}
+
+	encodedBytes = append(encodedBytes, slipEnd)
+	return encodedBytes
+}
+
+// SLIPDecode returns the first frame in bytes along with whatever bytes follow it
+func SLIPDecode(bytes []byte) ([]byte, []byte, error) {
+	decodedBytes := []byte{}
+	escapeNext := false
+
+	for index, packetByte := range bytes {
+		if escapeNext {
+			switch packetByte {
+			case slipEscEnd:
+				decodedBytes = append(decodedBytes, slipEnd)
+			case slipEscEsc:
+				decodedBytes = append(decodedBytes, slipEsc)
+			default:
+				return nil, bytes[index+1:], errors.New("SLIP escape byte followed by invalid byte")
+			}
+			escapeNext = false
+			continue
+		}
+
+		switch packetByte {
+		case slipEsc:
+			escapeNext = true
+		case slipEnd:
+			if len(decodedBytes) > 0 {
+				return decodedBytes, bytes[index+1:], nil
+			}
+			// NOTE: opening END byte or an empty frame, can discard
+		default:
+			decodedBytes = append(decodedBytes, packetByte)
+		}
+	}
+	return nil, bytes, errors.New("SLIP frame is not terminated")
+}
+
+func readSLIPFrame(reader *bufio.Reader) ([]byte, error) {
+	frame := []byte{}
+	escapeNext := false
+	var frameErr error
+
+	for {
+		// NOTE: a peer that never sends END would otherwise grow the frame forever
+		if len(frame) > maxStreamPacketSize {
+			return nil, fmt.Errorf("SLIP frame is over %d bytes", maxStreamPacketSize)
+		}
+
+		packetByte, err := reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if escapeNext {
+			switch packetByte {
+			case slipEscEnd:
+				frame = append(frame, slipEnd)
+			case slipEscEsc:
+				frame = append(frame, slipEsc)
+			default:
+				frameErr = errors.New("SLIP escape byte followed by invalid byte")
+			}
+			escapeNext = false
+			continue
+		}
+
+		switch packetByte {
+		case slipEsc:
+			escapeNext = true
+		case slipEnd:
+			if len(frame) > 0 || frameErr != nil {
+				return frame, frameErr
+			}
+		default:
+			frame = append(frame, packetByte)
+		}
+	}
+}
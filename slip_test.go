@@ -0,0 +1,83 @@
+package osc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSLIPEncoding(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		bytes    []byte
+		expected []byte
+	}{
+		{
+			name:     "plain bytes",
+			bytes:    []byte{47, 104, 105, 0},
+			expected: []byte{0xc0, 47, 104, 105, 0, 0xc0},
+		},
+		{
+			name:     "escaped bytes",
+			bytes:    []byte{1, 0xc0, 2, 0xdb, 3},
+			expected: []byte{0xc0, 1, 0xdb, 0xdc, 2, 0xdb, 0xdd, 3, 0xc0},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := SLIPEncode(testCase.bytes)
+
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to encode properly got '%v', expected '%v'", got, testCase.expected)
+			}
+
+			decoded, remaining, err := SLIPDecode(got)
+			if err != nil {
+				t.Fatalf("failed to decode: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(decoded, testCase.bytes) {
+				t.Fatalf("failed to decode properly got '%v', expected '%v'", decoded, testCase.bytes)
+			}
+
+			if len(remaining) != 0 {
+				t.Fatalf("failed to decode properly got remaining bytes '%v'", remaining)
+			}
+		})
+	}
+}
+
+func TestBadSLIPDecoding(t *testing.T) {
+
+	testCases := []struct {
+		name        string
+		bytes       []byte
+		errorString string
+	}{
+		{
+			name:        "unterminated frame",
+			bytes:       []byte{0xc0, 1, 2, 3},
+			errorString: "SLIP frame is not terminated",
+		},
+		{
+			name:        "bad escape",
+			bytes:       []byte{0xc0, 1, 0xdb, 3, 0xc0},
+			errorString: "SLIP escape byte followed by invalid byte",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, _, err := SLIPDecode(testCase.bytes)
+
+			if err == nil {
+				t.Fatalf("SLIPDecode expected to fail but got: %+v", got)
+			}
+
+			if err.Error() != testCase.errorString {
+				t.Fatalf("SLIPDecode got error '%s', expected '%s'", err.Error(), testCase.errorString)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix && !windows
+
+package osc
+
+import "errors"
+
+func setBroadcast(fd uintptr) error {
+	return errors.New("broadcast is not supported on this platform")
+}
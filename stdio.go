@@ -0,0 +1,51 @@
+package osc
+
+import (
+	"io"
+	"os"
+)
+
+type ioAddr string
+
+func (a ioAddr) Network() string {
+	return "io"
+}
+
+func (a ioAddr) String() string {
+	return string(a)
+}
+
+type readWriteCloser struct {
+	io.Reader
+	io.Writer
+	close func() error
+}
+
+func (rw readWriteCloser) Close() error {
+	return rw.close()
+}
+
+// NewIOConn exchanges framed OSC packets over rw, Close closes rw if it is an io.Closer
+func NewIOConn(rw io.ReadWriter, framing Framing) *StreamConn {
+	conn := readWriteCloser{
+		Reader: rw,
+		Writer: rw,
+		close: func() error {
+			if closer, ok := rw.(io.Closer); ok {
+				return closer.Close()
+			}
+			return nil
+		},
+	}
+	return newStreamConn(conn, framing, ioAddr("io"), ioAddr("io"))
+}
+
+// NewStdioConn reads framed OSC packets from stdin and writes them to stdout
+func NewStdioConn(framing Framing) *StreamConn {
+	conn := readWriteCloser{
+		Reader: os.Stdin,
+		Writer: os.Stdout,
+		close:  os.Stdin.Close,
+	}
+	return newStreamConn(conn, framing, ioAddr("stdio"), ioAddr("stdio"))
+}
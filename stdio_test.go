@@ -0,0 +1,60 @@
+package osc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestIOConnRoundTrip(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		framing Framing
+	}{
+		{name: "size framing", framing: SizeFraming},
+		{name: "slip framing", framing: SLIPFraming},
+	}
+
+	packets := []OSCPacket{
+		&OSCMessage{Address: "/hello", Args: []OSCArg{{Type: "s", Value: "world"}}},
+		&OSCMessage{Address: "/blob", Args: []OSCArg{{Type: "b", Value: []byte{0xc0, 0xdb, 0, 0}}}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			conn := NewIOConn(buffer, testCase.framing)
+
+			for _, packet := range packets {
+				if err := conn.WritePacket(packet); err != nil {
+					t.Fatalf("failed to write packet: %s", err.Error())
+				}
+			}
+
+			for _, expected := range packets {
+				got, source, err := conn.ReadPacket()
+				if err != nil {
+					t.Fatalf("failed to read packet: %s", err.Error())
+				}
+				if !reflect.DeepEqual(got, expected) {
+					t.Fatalf("failed to receive packet got '%v', expected '%v'", got, expected)
+				}
+				if source.Network() != "io" {
+					t.Fatalf("failed to set source network got '%s', expected 'io'", source.Network())
+				}
+			}
+
+			_, _, err := conn.ReadPacket()
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("expected EOF after the buffer was drained but got: %v", err)
+			}
+
+			if err := conn.Close(); err != nil {
+				t.Fatalf("failed to close conn wrapping a non-closer: %s", err.Error())
+			}
+		})
+	}
+}
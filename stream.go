@@ -0,0 +1,168 @@
+package osc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+type Framing int
+
+const (
+	// SizeFraming prefixes each packet with its size as an int32 like OSC 1.0
+	SizeFraming Framing = iota
+	// SLIPFraming wraps each packet in SLIP END bytes like OSC 1.1
+	SLIPFraming
+)
+
+// NOTE: guards against allocating whatever a corrupt size prefix claims or a SLIP frame that never ends
+const maxStreamPacketSize = 16 * 1024 * 1024
+
+func (f Framing) String() string {
+	switch f {
+	case SizeFraming:
+		return "size"
+	case SLIPFraming:
+		return "slip"
+	default:
+		return fmt.Sprintf("Framing(%d)", int(f))
+	}
+}
+
+// StreamConn reads and writes framed OSC packets over a stream like TCP, a unix socket or a serial port
+type StreamConn struct {
+	conn       io.ReadWriteCloser
+	reader     *bufio.Reader
+	framing    Framing
+	localAddr  net.Addr
+	remoteAddr net.Addr
+	writeMutex sync.Mutex
+}
+
+func NewStreamConn(conn net.Conn, framing Framing) *StreamConn {
+	return newStreamConn(conn, framing, conn.LocalAddr(), conn.RemoteAddr())
+}
+
+func newStreamConn(conn io.ReadWriteCloser, framing Framing, localAddr net.Addr, remoteAddr net.Addr) *StreamConn {
+	return &StreamConn{
+		conn:       conn,
+		reader:     bufio.NewReader(conn),
+		framing:    framing,
+		localAddr:  localAddr,
+		remoteAddr: remoteAddr,
+	}
+}
+
+func DialStream(network string, address string, framing Framing) (*StreamConn, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return NewStreamConn(conn, framing), nil
+}
+
+func (c *StreamConn) ReadPacket() (OSCPacket, net.Addr, error) {
+	var frame []byte
+	var err error
+
+	switch c.framing {
+	case SLIPFraming:
+		frame, err = readSLIPFrame(c.reader)
+		if err != nil && frame != nil {
+			return nil, c.RemoteAddr(), &DecodeError{Bytes: frame, Err: err}
+		}
+	case SizeFraming:
+		frame, err = readSizeFrame(c.reader)
+	default:
+		err = fmt.Errorf("unsupported framing: %s", c.framing)
+	}
+	if err != nil {
+		return nil, c.RemoteAddr(), err
+	}
+
+	packet, _, err := PacketFromBytes(frame)
+	if err != nil {
+		return nil, c.RemoteAddr(), &DecodeError{Bytes: frame, Err: err}
+	}
+	return packet, c.RemoteAddr(), nil
+}
+
+func readSizeFrame(reader *bufio.Reader) ([]byte, error) {
+	sizeBytes := make([]byte, 4)
+	if _, err := io.ReadFull(reader, sizeBytes); err != nil {
+		return nil, err
+	}
+
+	size := int32(binary.BigEndian.Uint32(sizeBytes))
+	if size <= 0 || size > maxStreamPacketSize {
+		return nil, fmt.Errorf("stream packet size %d is not valid", size)
+	}
+
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(reader, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+func (c *StreamConn) WritePacket(packet OSCPacket) error {
+	packetBytes, err := packet.ToBytes()
+	if err != nil {
+		return err
+	}
+
+	switch c.framing {
+	case SLIPFraming:
+		packetBytes = SLIPEncode(packetBytes)
+	case SizeFraming:
+		packetBytes = append(int32ToOSCBytes(int32(len(packetBytes))), packetBytes...)
+	default:
+		return fmt.Errorf("unsupported framing: %s", c.framing)
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	_, err = c.conn.Write(packetBytes)
+	return err
+}
+
+func (c *StreamConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *StreamConn) LocalAddr() net.Addr {
+	return c.localAddr
+}
+
+func (c *StreamConn) RemoteAddr() net.Addr {
+	return c.remoteAddr
+}
+
+// StreamListener accepts stream connections and wraps them as StreamConns
+type StreamListener struct {
+	net.Listener
+	framing Framing
+}
+
+func ListenStream(network string, address string, framing Framing) (*StreamListener, error) {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamListener{
+		Listener: listener,
+		framing:  framing,
+	}, nil
+}
+
+func (l *StreamListener) AcceptStream() (*StreamConn, error) {
+	conn, err := l.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return NewStreamConn(conn, l.framing), nil
+}
@@ -0,0 +1,162 @@
+package osc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestStreamConnRoundTrip(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		framing Framing
+	}{
+		{name: "size framing", framing: SizeFraming},
+		{name: "slip framing", framing: SLIPFraming},
+	}
+
+	packets := []OSCPacket{
+		&OSCMessage{Address: "/hello", Args: []OSCArg{{Type: "b", Value: []byte{0xc0, 0xdb, 0}}}},
+		&OSCBundle{
+			TimeTag:  ImmediateTimeTag(),
+			Contents: []OSCPacket{&OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: int32(1)}}}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			writer := NewStreamConn(client, testCase.framing)
+			reader := NewStreamConn(server, testCase.framing)
+
+			go func() {
+				for _, packet := range packets {
+					writer.WritePacket(packet)
+				}
+				writer.Close()
+			}()
+
+			for _, expected := range packets {
+				got, _, err := reader.ReadPacket()
+				if err != nil {
+					t.Fatalf("failed to read packet: %s", err.Error())
+				}
+				if !reflect.DeepEqual(got, expected) {
+					t.Fatalf("failed to receive packet got '%v', expected '%v'", got, expected)
+				}
+			}
+
+			_, _, err := reader.ReadPacket()
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("expected EOF after the writer closed but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestStreamConnDecodeError(t *testing.T) {
+
+	testCases := []struct {
+		name    string
+		framing Framing
+		bytes   []byte
+	}{
+		{
+			name:    "size framing",
+			framing: SizeFraming,
+			bytes:   []byte{0, 0, 0, 4, 'j', 'u', 'n', 'k', 0, 0, 0, 4, '/', 'h', 'i', 0},
+		},
+		{
+			name:    "slip framing",
+			framing: SLIPFraming,
+			bytes:   []byte{0xc0, 'j', 'u', 'n', 'k', 0xc0, 0xc0, '/', 'h', 'i', 0, 0xc0},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client, server := net.Pipe()
+			reader := NewStreamConn(server, testCase.framing)
+
+			go func() {
+				client.Write(testCase.bytes)
+				client.Close()
+			}()
+
+			_, _, err := reader.ReadPacket()
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("expected a DecodeError but got: %v", err)
+			}
+
+			if string(decodeErr.Bytes) != "junk" {
+				t.Fatalf("DecodeError got bytes '%v', expected '%v'", decodeErr.Bytes, []byte("junk"))
+			}
+
+			got, _, err := reader.ReadPacket()
+			if err != nil {
+				t.Fatalf("failed to read packet after DecodeError: %s", err.Error())
+			}
+
+			expected := &OSCMessage{Address: "/hi", Args: []OSCArg{}}
+			if !reflect.DeepEqual(got, expected) {
+				t.Fatalf("failed to receive packet got '%v', expected '%v'", got, expected)
+			}
+		})
+	}
+}
+
+func TestStreamConnBadSize(t *testing.T) {
+	client, server := net.Pipe()
+	reader := NewStreamConn(server, SizeFraming)
+
+	go func() {
+		client.Write([]byte{0xff, 0xff, 0xff, 0xff})
+		client.Close()
+	}()
+
+	_, _, err := reader.ReadPacket()
+	if err == nil {
+		t.Fatalf("ReadPacket expected to fail with a negative size")
+	}
+
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		t.Fatalf("a bad size prefix should not be recoverable but got: %v", err)
+	}
+}
+
+func TestStreamConnSLIPFrameTooLong(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	reader := NewStreamConn(server, SLIPFraming)
+
+	go func() {
+		chunk := bytes.Repeat([]byte{'a'}, 64*1024)
+		for written := 0; written <= maxStreamPacketSize; written += len(chunk) {
+			if _, err := client.Write(chunk); err != nil {
+				return
+			}
+		}
+		client.Close()
+	}()
+
+	_, _, err := reader.ReadPacket()
+	if err == nil {
+		t.Fatalf("ReadPacket expected to fail with a frame that never ends")
+	}
+	expected := fmt.Sprintf("SLIP frame is over %d bytes", maxStreamPacketSize)
+	if err.Error() != expected {
+		t.Fatalf("failed to reject frame got '%s', expected '%s'", err.Error(), expected)
+	}
+
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		t.Fatalf("an oversized SLIP frame should not be recoverable but got: %v", err)
+	}
+}
@@ -0,0 +1,352 @@
+package osc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+func (c OSCColor) String() string {
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.r, c.g, c.b, c.a)
+}
+
+type colorJSON struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+	A uint8 `json:"a"`
+}
+
+func (c OSCColor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(colorJSON{R: c.r, G: c.g, B: c.b, A: c.a})
+}
+
+func (c *OSCColor) UnmarshalJSON(data []byte) error {
+	var color colorJSON
+	if err := json.Unmarshal(data, &color); err != nil {
+		return err
+	}
+	*c = OSCColor{r: color.R, g: color.G, b: color.B, a: color.A}
+	return nil
+}
+
+func (a OSCArg) String() string {
+	switch value := a.Value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case []byte:
+		return "0x" + hex.EncodeToString(value)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// String renders the message in the text syntax: /address ,types arg1 arg2
+func (m *OSCMessage) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(m.Address)
+	sb.WriteString(" ,")
+	for _, arg := range m.Args {
+		sb.WriteString(arg.Type)
+	}
+
+	for _, arg := range m.Args {
+		switch arg.Type {
+		case "T", "F", "N", "I":
+			// NOTE: these types carry no data, the type tag says it all
+			continue
+		}
+		sb.WriteString(" ")
+		sb.WriteString(arg.String())
+	}
+	return sb.String()
+}
+
+// String renders the bundle in the text syntax: #bundle timetag [packet; packet]
+func (b *OSCBundle) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("#bundle ")
+	sb.WriteString(b.TimeTag.String())
+	sb.WriteString(" [")
+	for index, packet := range b.Contents {
+		if index > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(fmt.Sprint(packet))
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// PacketFromText parses the text syntax produced by String, the type tag may be left off to infer types from the args
+func PacketFromText(text string) (OSCPacket, error) {
+	parser := &textParser{text: text}
+	packet, err := parser.parsePacket()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipSpace()
+	if !parser.done() {
+		return nil, fmt.Errorf("unexpected text after OSC packet: %s", parser.text[parser.position:])
+	}
+	return packet, nil
+}
+
+type textParser struct {
+	text     string
+	position int
+}
+
+func (p *textParser) done() bool {
+	return p.position >= len(p.text)
+}
+
+func (p *textParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.text[p.position]
+}
+
+func (p *textParser) skipSpace() {
+	for !p.done() && unicode.IsSpace(rune(p.peek())) {
+		p.position++
+	}
+}
+
+// atArgEnd reports whether the args of the current message are finished
+func (p *textParser) atArgEnd() bool {
+	p.skipSpace()
+	return p.done() || p.peek() == ';' || p.peek() == ']'
+}
+
+// token reads a bare word or a Go style quoted string
+func (p *textParser) token() (string, bool, error) {
+	p.skipSpace()
+	if p.peek() == '"' {
+		quoted, err := strconv.QuotedPrefix(p.text[p.position:])
+		if err != nil {
+			return "", true, errors.New("OSC text has an unterminated string")
+		}
+		p.position += len(quoted)
+		value, err := strconv.Unquote(quoted)
+		return value, true, err
+	}
+
+	start := p.position
+	for !p.done() && !unicode.IsSpace(rune(p.peek())) && p.peek() != ';' && p.peek() != ']' {
+		p.position++
+	}
+	return p.text[start:p.position], false, nil
+}
+
+func (p *textParser) parsePacket() (OSCPacket, error) {
+	p.skipSpace()
+	if strings.HasPrefix(p.text[p.position:], "#bundle") {
+		p.position += len("#bundle")
+		return p.parseBundle()
+	}
+	if p.peek() == '/' {
+		return p.parseMessage()
+	}
+	return nil, errors.New("OSC packet text must start with / for a message or #bundle for a bundle")
+}
+
+func (p *textParser) parseBundle() (*OSCBundle, error) {
+	timeTagText, _, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	timeTag, err := timeTagFromText(timeTagText)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.peek() != '[' {
+		return nil, errors.New("OSC bundle text must have contents in []")
+	}
+	p.position++
+
+	bundle := &OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{}}
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.position++
+		return bundle, nil
+	}
+
+	for {
+		packet, err := p.parsePacket()
+		if err != nil {
+			return nil, err
+		}
+		bundle.Contents = append(bundle.Contents, packet)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ';':
+			p.position++
+		case ']':
+			p.position++
+			return bundle, nil
+		default:
+			return nil, errors.New("OSC bundle text has unclosed [")
+		}
+	}
+}
+
+func (p *textParser) parseMessage() (*OSCMessage, error) {
+	address, _, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	message := &OSCMessage{Address: address, Args: []OSCArg{}}
+
+	p.skipSpace()
+	if p.peek() != ',' {
+		for !p.atArgEnd() {
+			value, quoted, err := p.token()
+			if err != nil {
+				return nil, err
+			}
+			message.Args = append(message.Args, inferArgFromText(value, quoted))
+		}
+		return message, nil
+	}
+
+	typeTag, _, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	for _, oscType := range typeTag[1:] {
+		switch oscType {
+		case 'T':
+			message.Args = append(message.Args, OSCArg{Type: "T", Value: true})
+			continue
+		case 'F':
+			message.Args = append(message.Args, OSCArg{Type: "F", Value: false})
+			continue
+		case 'N':
+			message.Args = append(message.Args, OSCArg{Type: "N", Value: nil})
+			continue
+		case 'I':
+			message.Args = append(message.Args, OSCArg{Type: "I", Value: math.MaxInt32})
+			continue
+		}
+
+		if p.atArgEnd() {
+			return nil, fmt.Errorf("OSC message text is missing a value for type %c", oscType)
+		}
+		value, _, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		arg, err := argFromText(value, string(oscType))
+		if err != nil {
+			return nil, err
+		}
+		message.Args = append(message.Args, arg)
+	}
+
+	if !p.atArgEnd() {
+		return nil, errors.New("OSC message text has more args than types")
+	}
+	return message, nil
+}
+
+func argFromText(value string, oscType string) (OSCArg, error) {
+	arg := OSCArg{Type: oscType}
+	switch oscType {
+	case "s":
+		arg.Value = value
+	case "i":
+		number, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return OSCArg{}, fmt.Errorf("OSC arg had int32 type but value %s is not an int32", value)
+		}
+		arg.Value = int32(number)
+	case "h":
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return OSCArg{}, fmt.Errorf("OSC arg had int64 type but value %s is not an int64", value)
+		}
+		arg.Value = number
+	case "f":
+		number, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return OSCArg{}, fmt.Errorf("OSC arg had float32 type but value %s is not a number", value)
+		}
+		arg.Value = float32(number)
+	case "d":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return OSCArg{}, fmt.Errorf("OSC arg had float64 type but value %s is not a number", value)
+		}
+		arg.Value = number
+	case "b":
+		blob, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+		if err != nil {
+			return OSCArg{}, fmt.Errorf("OSC arg had blob type but value %s is not hex", value)
+		}
+		arg.Value = blob
+	case "r":
+		color, err := hex.DecodeString(strings.TrimPrefix(value, "#"))
+		if err != nil || len(color) != 4 {
+			return OSCArg{}, fmt.Errorf("OSC arg had color type but value %s is not #rrggbbaa", value)
+		}
+		arg.Value = OSCColor{r: color[0], g: color[1], b: color[2], a: color[3]}
+	case "t":
+		timeTag, err := timeTagFromText(value)
+		if err != nil {
+			return OSCArg{}, err
+		}
+		arg.Value = timeTag
+	default:
+		return OSCArg{}, fmt.Errorf("unsupported OSC argument type: %s", oscType)
+	}
+	return arg, nil
+}
+
+func inferArgFromText(value string, quoted bool) OSCArg {
+	if quoted {
+		return OSCArg{Type: "s", Value: value}
+	}
+	if number, err := strconv.ParseInt(value, 10, 32); err == nil {
+		return OSCArg{Type: "i", Value: int32(number)}
+	}
+	if number, err := strconv.ParseFloat(value, 32); err == nil {
+		return OSCArg{Type: "f", Value: float32(number)}
+	}
+	switch value {
+	case "true":
+		return OSCArg{Type: "T", Value: true}
+	case "false":
+		return OSCArg{Type: "F", Value: false}
+	case "nil":
+		return OSCArg{Type: "N", Value: nil}
+	}
+	return OSCArg{Type: "s", Value: value}
+}
+
+func timeTagFromText(text string) (OSCTimeTag, error) {
+	if text == "immediate" {
+		return ImmediateTimeTag(), nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return OSCTimeTag{}, fmt.Errorf("OSC time tag text must be immediate or RFC3339 but got %s", text)
+	}
+	return TimeTagFromTime(parsed), nil
+}
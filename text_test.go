@@ -0,0 +1,181 @@
+package osc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOSCPacketString(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		packet   OSCPacket
+		expected string
+	}{
+		{
+			name:     "message without args",
+			packet:   &OSCMessage{Address: "/hello", Args: []OSCArg{}},
+			expected: "/hello ,",
+		},
+		{
+			name: "message with args",
+			packet: &OSCMessage{Address: "/hello", Args: []OSCArg{
+				{Type: "s", Value: "arg 1"},
+				{Type: "i", Value: int32(35)},
+				{Type: "f", Value: float32(0.75)},
+				{Type: "T", Value: true},
+				{Type: "b", Value: []byte{0xde, 0xad}},
+				{Type: "d", Value: 12.5},
+				{Type: "r", Value: OSCColor{r: 255, g: 0, b: 16, a: 1}},
+			}},
+			expected: `/hello ,sifTbdr "arg 1" 35 0.75 0xdead 12.5 #ff001001`,
+		},
+		{
+			name: "nested bundle",
+			packet: &OSCBundle{
+				TimeTag: ImmediateTimeTag(),
+				Contents: []OSCPacket{
+					&OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: 1}}},
+					&OSCBundle{
+						TimeTag:  NewOSCTimeTag(2208988800, 0),
+						Contents: []OSCPacket{&OSCMessage{Address: "/b"}},
+					},
+				},
+			},
+			expected: "#bundle immediate [/a ,i 1; #bundle 1970-01-01T00:00:00Z [/b ,]]",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := testCase.packet.(interface{ String() string }).String()
+
+			if got != testCase.expected {
+				t.Fatalf("failed to format packet got '%s', expected '%s'", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestPacketFromText(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		text     string
+		expected OSCPacket
+	}{
+		{
+			name:     "message without args",
+			text:     "/hello ,",
+			expected: &OSCMessage{Address: "/hello", Args: []OSCArg{}},
+		},
+		{
+			name: "message with typed args",
+			text: `/hello ,sifTbdr "arg 1" 35 0.75 0xdead 12.5 #ff001001`,
+			expected: &OSCMessage{Address: "/hello", Args: []OSCArg{
+				{Type: "s", Value: "arg 1"},
+				{Type: "i", Value: int32(35)},
+				{Type: "f", Value: float32(0.75)},
+				{Type: "T", Value: true},
+				{Type: "b", Value: []byte{0xde, 0xad}},
+				{Type: "d", Value: 12.5},
+				{Type: "r", Value: OSCColor{r: 255, g: 0, b: 16, a: 1}},
+			}},
+		},
+		{
+			name: "message with inferred args",
+			text: `/fader 1 0.5 "2" on`,
+			expected: &OSCMessage{Address: "/fader", Args: []OSCArg{
+				{Type: "i", Value: int32(1)},
+				{Type: "f", Value: float32(0.5)},
+				{Type: "s", Value: "2"},
+				{Type: "s", Value: "on"},
+			}},
+		},
+		{
+			name: "nested bundle",
+			text: "#bundle immediate [/a ,i 1; #bundle 1970-01-01T00:00:00Z [/b ,]]",
+			expected: &OSCBundle{
+				TimeTag: ImmediateTimeTag(),
+				Contents: []OSCPacket{
+					&OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: int32(1)}}},
+					&OSCBundle{
+						TimeTag:  NewOSCTimeTag(2208988800, 0),
+						Contents: []OSCPacket{&OSCMessage{Address: "/b", Args: []OSCArg{}}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := PacketFromText(testCase.text)
+			if err != nil {
+				t.Fatalf("failed to parse text: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(got, testCase.expected) {
+				t.Fatalf("failed to parse text got '%v', expected '%v'", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestPacketFromTextErrors(t *testing.T) {
+
+	testCases := []struct {
+		name          string
+		text          string
+		expectedError string
+	}{
+		{
+			name:          "no address",
+			text:          "hello ,i 1",
+			expectedError: "OSC packet text must start with / for a message or #bundle for a bundle",
+		},
+		{
+			name:          "missing value",
+			text:          "/hello ,ii 1",
+			expectedError: "OSC message text is missing a value for type i",
+		},
+		{
+			name:          "extra value",
+			text:          "/hello ,i 1 2",
+			expectedError: "OSC message text has more args than types",
+		},
+		{
+			name:          "bad int",
+			text:          "/hello ,i one",
+			expectedError: "OSC arg had int32 type but value one is not an int32",
+		},
+		{
+			name:          "unterminated string",
+			text:          `/hello ,s "one`,
+			expectedError: "OSC text has an unterminated string",
+		},
+		{
+			name:          "unclosed bundle",
+			text:          "#bundle immediate [/a ,i 1",
+			expectedError: "OSC bundle text has unclosed [",
+		},
+		{
+			name:          "trailing text",
+			text:          "#bundle immediate [/a ,i 1] /b",
+			expectedError: "unexpected text after OSC packet: /b",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := PacketFromText(testCase.text)
+			if err == nil {
+				t.Fatalf("expected an error but got none")
+			}
+
+			if err.Error() != testCase.expectedError {
+				t.Fatalf("failed to report error got '%s', expected '%s'", err.Error(), testCase.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package osc
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// NOTE: OSC time tags are NTP timestamps which count from 1900-01-01
+const ntpEpochOffset = 2208988800
+
+func NewOSCTimeTag(seconds uint32, fractionalSeconds uint32) OSCTimeTag {
+	return OSCTimeTag{
+		seconds:           int32(seconds),
+		fractionalSeconds: int32(fractionalSeconds),
+	}
+}
+
+func ImmediateTimeTag() OSCTimeTag {
+	return NewOSCTimeTag(0, 1)
+}
+
+func TimeTagFromTime(t time.Time) OSCTimeTag {
+	seconds := uint32(t.Unix() + ntpEpochOffset)
+	fractionalSeconds := uint32((uint64(t.Nanosecond()) << 32) / uint64(time.Second))
+	return NewOSCTimeTag(seconds, fractionalSeconds)
+}
+
+func (t OSCTimeTag) Seconds() uint32 {
+	return uint32(t.seconds)
+}
+
+func (t OSCTimeTag) FractionalSeconds() uint32 {
+	return uint32(t.fractionalSeconds)
+}
+
+func (t OSCTimeTag) IsImmediate() bool {
+	return t.Seconds() == 0 && t.FractionalSeconds() == 1
+}
+
+func (t OSCTimeTag) Time() time.Time {
+	seconds := int64(t.Seconds()) - ntpEpochOffset
+	nanoseconds := (uint64(t.FractionalSeconds()) * uint64(time.Second)) >> 32
+	return time.Unix(seconds, int64(nanoseconds)).UTC()
+}
+
+func (t OSCTimeTag) String() string {
+	if t.IsImmediate() {
+		return "immediate"
+	}
+	return t.Time().Format(time.RFC3339Nano)
+}
+
+type timeTagJSON struct {
+	Seconds           uint32 `json:"seconds"`
+	FractionalSeconds uint32 `json:"fractionalSeconds"`
+}
+
+func (t OSCTimeTag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(timeTagJSON{
+		Seconds:           t.Seconds(),
+		FractionalSeconds: t.FractionalSeconds(),
+	})
+}
+
+func (t *OSCTimeTag) UnmarshalJSON(data []byte) error {
+	var timeTag timeTagJSON
+	if err := json.Unmarshal(data, &timeTag); err != nil {
+		return err
+	}
+	*t = NewOSCTimeTag(timeTag.Seconds, timeTag.FractionalSeconds)
+	return nil
+}
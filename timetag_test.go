@@ -0,0 +1,101 @@
+package osc
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestOSCTimeTagTime(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		timeTag  OSCTimeTag
+		expected time.Time
+	}{
+		{
+			name:     "unix epoch",
+			timeTag:  NewOSCTimeTag(2208988800, 0),
+			expected: time.Unix(0, 0).UTC(),
+		},
+		{
+			name:     "half second",
+			timeTag:  NewOSCTimeTag(2208988801, 1<<31),
+			expected: time.Unix(1, 500000000).UTC(),
+		},
+		{
+			name:     "seconds past int32",
+			timeTag:  NewOSCTimeTag(3969446400, 0),
+			expected: time.Date(2025, time.October, 14, 16, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := testCase.timeTag.Time()
+
+			if !got.Equal(testCase.expected) {
+				t.Fatalf("failed to convert time tag got '%v', expected '%v'", got, testCase.expected)
+			}
+
+			roundTrip := TimeTagFromTime(got)
+			if roundTrip != testCase.timeTag {
+				t.Fatalf("failed to convert time back to time tag got '%+v', expected '%+v'", roundTrip, testCase.timeTag)
+			}
+		})
+	}
+}
+
+func TestOSCTimeTagString(t *testing.T) {
+
+	testCases := []struct {
+		name     string
+		timeTag  OSCTimeTag
+		expected string
+	}{
+		{
+			name:     "immediate",
+			timeTag:  ImmediateTimeTag(),
+			expected: "immediate",
+		},
+		{
+			name:     "unix epoch",
+			timeTag:  NewOSCTimeTag(2208988800, 0),
+			expected: "1970-01-01T00:00:00Z",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := testCase.timeTag.String()
+
+			if got != testCase.expected {
+				t.Fatalf("failed to format time tag got '%s', expected '%s'", got, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestOSCTimeTagJSON(t *testing.T) {
+	timeTag := NewOSCTimeTag(3969446400, 1)
+
+	got, err := json.Marshal(timeTag)
+	if err != nil {
+		t.Fatalf("failed to marshal time tag: %s", err.Error())
+	}
+
+	expected := `{"seconds":3969446400,"fractionalSeconds":1}`
+	if string(got) != expected {
+		t.Fatalf("failed to marshal time tag got '%s', expected '%s'", got, expected)
+	}
+
+	var decoded OSCTimeTag
+	err = json.Unmarshal(got, &decoded)
+	if err != nil {
+		t.Fatalf("failed to unmarshal time tag: %s", err.Error())
+	}
+
+	if decoded != timeTag {
+		t.Fatalf("failed to unmarshal time tag got '%+v', expected '%+v'", decoded, timeTag)
+	}
+}
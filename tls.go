@@ -0,0 +1,35 @@
+package osc
+
+import (
+	"crypto/tls"
+)
+
+// DialTLS connects to an OSC endpoint over TLS, network is usually tcp, tcp4 or tcp6
+func DialTLS(network string, address string, framing Framing, config *tls.Config) (*StreamConn, error) {
+	conn, err := tls.Dial(network, address, config)
+	if err != nil {
+		return nil, err
+	}
+	return NewStreamConn(conn, framing), nil
+}
+
+// ListenTLS accepts OSC connections over TLS, config must have at least one certificate
+func ListenTLS(network string, address string, framing Framing, config *tls.Config) (*StreamListener, error) {
+	listener, err := tls.Listen(network, address, config)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamListener{
+		Listener: listener,
+		framing:  framing,
+	}, nil
+}
+
+// ListenAndServeTLS is like ListenAndServe for stream networks but every connection is wrapped in TLS
+func (s *Server) ListenAndServeTLS(network string, address string, config *tls.Config) error {
+	listener, err := ListenTLS(network, address, s.Framing, config)
+	if err != nil {
+		return err
+	}
+	return s.ServeStream(listener)
+}
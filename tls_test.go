@@ -0,0 +1,127 @@
+package osc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testCertificate(t *testing.T, commonName string) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %s", err.Error())
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %s", err.Error())
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(certificate)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+func TestTLSEcho(t *testing.T) {
+	serverCertificate, serverPool := testCertificate(t, "server")
+	clientCertificate, clientPool := testCertificate(t, "client")
+
+	testCases := []struct {
+		name         string
+		serverConfig *tls.Config
+		clientConfig *tls.Config
+		shouldFail   bool
+	}{
+		{
+			name:         "server authentication",
+			serverConfig: &tls.Config{Certificates: []tls.Certificate{serverCertificate}},
+			clientConfig: &tls.Config{RootCAs: serverPool},
+		},
+		{
+			name: "mutual authentication",
+			serverConfig: &tls.Config{
+				Certificates: []tls.Certificate{serverCertificate},
+				ClientCAs:    clientPool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+			},
+			clientConfig: &tls.Config{RootCAs: serverPool, Certificates: []tls.Certificate{clientCertificate}},
+		},
+		{
+			name: "missing client certificate",
+			serverConfig: &tls.Config{
+				Certificates: []tls.Certificate{serverCertificate},
+				ClientCAs:    clientPool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+			},
+			clientConfig: &tls.Config{RootCAs: serverPool},
+			shouldFail:   true,
+		},
+		{
+			name:         "skip verify",
+			serverConfig: &tls.Config{Certificates: []tls.Certificate{serverCertificate}},
+			clientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			listener, err := ListenTLS("tcp", "127.0.0.1:0", SLIPFraming, testCase.serverConfig)
+			if err != nil {
+				t.Fatalf("failed to listen: %s", err.Error())
+			}
+			server := &Server{Handler: echoHandler(t)}
+			go server.ServeStream(listener)
+			defer listener.Close()
+
+			client, err := DialTLS("tcp", listener.Addr().String(), SLIPFraming, testCase.clientConfig)
+			if err != nil {
+				t.Fatalf("failed to dial: %s", err.Error())
+			}
+			defer client.Close()
+
+			message := &OSCMessage{Address: "/secure", Args: []OSCArg{{Type: "i", Value: int32(1)}}}
+			err = client.WritePacket(message)
+			if err != nil {
+				t.Fatalf("failed to write packet: %s", err.Error())
+			}
+
+			got, _, err := client.ReadPacket()
+			if testCase.shouldFail {
+				if err == nil {
+					t.Fatalf("expected the server to reject a client without a certificate")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to read echo: %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, message) {
+				t.Fatalf("failed to echo packet got '%v', expected '%v'", got, message)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package osc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// NOTE: large enough for any UDP datagram so packets are never truncated
+const maxDatagramSize = 65535
+
+type UDPConn struct {
+	*net.UDPConn
+	buffer        []byte
+	maxPacketSize int
+}
+
+type UDPConfig struct {
+	// Broadcast enables SO_BROADCAST so packets can be sent to subnet broadcast addresses
+	Broadcast bool
+	// MulticastInterface is the interface used to send and join multicast groups, nil uses the system default
+	MulticastInterface *net.Interface
+	// MulticastTTL is the TTL/hop limit of outgoing multicast packets, 0 uses the system default of 1
+	MulticastTTL int
+	// DisableMulticastLoopback stops multicast packets sent from being delivered to the local host
+	DisableMulticastLoopback bool
+	// MaxPacketSize splits bundles written over this many bytes with SplitBundle and rejects messages over it, zero sends packets of any size
+	MaxPacketSize int
+}
+
+func DialUDP(network string, address string) (*UDPConn, error) {
+	return UDPConfig{}.Dial(network, address)
+}
+
+func ListenUDP(network string, address string) (*UDPConn, error) {
+	return UDPConfig{}.Listen(network, address)
+}
+
+func ListenMulticastUDP(network string, group string) (*UDPConn, error) {
+	return UDPConfig{}.ListenMulticast(network, group)
+}
+
+func (c UDPConfig) Dial(network string, address string) (*UDPConn, error) {
+	dialer := net.Dialer{Control: c.control}
+	conn, err := dialer.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	udpConn, ok := conn.(*net.UDPConn)
+	if !ok {
+		conn.Close()
+		return nil, errors.New("network must be one of udp, udp4 or udp6")
+	}
+
+	remoteAddr := udpConn.RemoteAddr().(*net.UDPAddr)
+	if remoteAddr.IP.IsMulticast() {
+		if err := c.setMulticastOptions(udpConn, remoteAddr.IP); err != nil {
+			udpConn.Close()
+			return nil, err
+		}
+	}
+	return newUDPConn(udpConn, c.MaxPacketSize), nil
+}
+
+func (c UDPConfig) Listen(network string, address string) (*UDPConn, error) {
+	listenConfig := net.ListenConfig{Control: c.control}
+	conn, err := listenConfig.ListenPacket(context.Background(), network, address)
+	if err != nil {
+		return nil, err
+	}
+	udpConn, ok := conn.(*net.UDPConn)
+	if !ok {
+		conn.Close()
+		return nil, errors.New("network must be one of udp, udp4 or udp6")
+	}
+	return newUDPConn(udpConn, c.MaxPacketSize), nil
+}
+
+// ListenMulticast joins the multicast group given as host:port and receives packets sent to it
+func (c UDPConfig) ListenMulticast(network string, group string) (*UDPConn, error) {
+	groupAddr, err := net.ResolveUDPAddr(network, group)
+	if err != nil {
+		return nil, err
+	}
+	if !groupAddr.IP.IsMulticast() {
+		return nil, errors.New("multicast group must be a multicast address")
+	}
+
+	udpConn, err := net.ListenMulticastUDP(network, c.MulticastInterface, groupAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.setMulticastOptions(udpConn, groupAddr.IP); err != nil {
+		udpConn.Close()
+		return nil, err
+	}
+	return newUDPConn(udpConn, c.MaxPacketSize), nil
+}
+
+func (c UDPConfig) control(network string, address string, rawConn syscall.RawConn) error {
+	if !c.Broadcast {
+		return nil
+	}
+	var sockoptErr error
+	err := rawConn.Control(func(fd uintptr) {
+		sockoptErr = setBroadcast(fd)
+	})
+	if err != nil {
+		return err
+	}
+	return sockoptErr
+}
+
+func (c UDPConfig) setMulticastOptions(conn *net.UDPConn, group net.IP) error {
+	if group.To4() != nil {
+		packetConn := ipv4.NewPacketConn(conn)
+		if c.MulticastInterface != nil {
+			if err := packetConn.SetMulticastInterface(c.MulticastInterface); err != nil {
+				return err
+			}
+		}
+		if c.MulticastTTL > 0 {
+			if err := packetConn.SetMulticastTTL(c.MulticastTTL); err != nil {
+				return err
+			}
+		}
+		return packetConn.SetMulticastLoopback(!c.DisableMulticastLoopback)
+	}
+
+	packetConn := ipv6.NewPacketConn(conn)
+	if c.MulticastInterface != nil {
+		if err := packetConn.SetMulticastInterface(c.MulticastInterface); err != nil {
+			return err
+		}
+	}
+	if c.MulticastTTL > 0 {
+		if err := packetConn.SetMulticastHopLimit(c.MulticastTTL); err != nil {
+			return err
+		}
+	}
+	return packetConn.SetMulticastLoopback(!c.DisableMulticastLoopback)
+}
+
+func newUDPConn(conn *net.UDPConn, maxPacketSize int) *UDPConn {
+	return &UDPConn{
+		UDPConn:       conn,
+		buffer:        make([]byte, maxDatagramSize),
+		maxPacketSize: maxPacketSize,
+	}
+}
+
+func (c *UDPConn) ReadPacket() (OSCPacket, net.Addr, error) {
+	return readDatagramPacket(c.UDPConn, c.buffer)
+}
+
+func (c *UDPConn) WritePacket(packet OSCPacket) error {
+	packets, err := c.split(packet)
+	if err != nil {
+		return err
+	}
+	for _, packet := range packets {
+		if err := writeDatagramPacket(c.UDPConn, packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *UDPConn) WritePacketTo(packet OSCPacket, addr net.Addr) error {
+	packets, err := c.split(packet)
+	if err != nil {
+		return err
+	}
+	for _, packet := range packets {
+		if err := writeDatagramPacketTo(c.UDPConn, packet, addr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// split enforces MaxPacketSize, packets that fit are passed on already encoded
+func (c *UDPConn) split(packet OSCPacket) ([]OSCPacket, error) {
+	if c.maxPacketSize <= 0 {
+		return []OSCPacket{packet}, nil
+	}
+
+	packetBytes, err := packet.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(packetBytes) <= c.maxPacketSize {
+		return []OSCPacket{encodedPacket{packet: packet, bytes: packetBytes}}, nil
+	}
+
+	packet = unwrapPacket(packet)
+	bundle, ok := packet.(*OSCBundle)
+	if !ok {
+		return nil, packetTooBigError(packet, len(packetBytes), c.maxPacketSize)
+	}
+	bundles, err := SplitBundle(bundle, c.maxPacketSize)
+	if err != nil {
+		return nil, err
+	}
+	packets := []OSCPacket{}
+	for _, bundle := range bundles {
+		packets = append(packets, bundle)
+	}
+	return packets, nil
+}
@@ -0,0 +1,321 @@
+package osc
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUDPConnRoundTrip(t *testing.T) {
+	server, err := ListenUDP("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer server.Close()
+
+	client, err := DialUDP("udp4", server.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	defer client.Close()
+
+	message := &OSCMessage{Address: "/hello", Args: []OSCArg{{Type: "b", Value: []byte{1, 2, 3}}}}
+	err = client.WritePacket(message)
+	if err != nil {
+		t.Fatalf("failed to write packet: %s", err.Error())
+	}
+
+	server.SetReadDeadline(time.Now().Add(time.Second))
+	got, from, err := server.ReadPacket()
+	if err != nil {
+		t.Fatalf("failed to read packet: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(got, message) {
+		t.Fatalf("failed to receive packet got '%v', expected '%v'", got, message)
+	}
+
+	reply := &OSCMessage{Address: "/reply", Args: []OSCArg{}}
+	err = server.WritePacketTo(reply, from)
+	if err != nil {
+		t.Fatalf("failed to write reply: %s", err.Error())
+	}
+
+	client.SetReadDeadline(time.Now().Add(time.Second))
+	got, _, err = client.ReadPacket()
+	if err != nil {
+		t.Fatalf("failed to read reply: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(got, reply) {
+		t.Fatalf("failed to receive reply got '%v', expected '%v'", got, reply)
+	}
+}
+
+func TestUDPConnIPv6(t *testing.T) {
+
+	testCases := []struct {
+		name          string
+		listenNetwork string
+		listenAddress string
+		dialNetwork   string
+		dialHost      string
+	}{
+		{
+			name:          "ipv6 loopback",
+			listenNetwork: "udp6",
+			listenAddress: "[::1]:0",
+			dialNetwork:   "udp6",
+			dialHost:      "::1",
+		},
+		{
+			name:          "dual-stack from ipv6",
+			listenNetwork: "udp",
+			listenAddress: "[::]:0",
+			dialNetwork:   "udp6",
+			dialHost:      "::1",
+		},
+		{
+			name:          "dual-stack from ipv4",
+			listenNetwork: "udp",
+			listenAddress: "[::]:0",
+			dialNetwork:   "udp4",
+			dialHost:      "127.0.0.1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			server, err := ListenUDP(testCase.listenNetwork, testCase.listenAddress)
+			if err != nil {
+				t.Skipf("IPv6 is not available: %s", err.Error())
+			}
+			defer server.Close()
+
+			port := server.LocalAddr().(*net.UDPAddr).Port
+			client, err := DialUDP(testCase.dialNetwork, net.JoinHostPort(testCase.dialHost, strconv.Itoa(port)))
+			if err != nil {
+				t.Skipf("IPv6 is not available: %s", err.Error())
+			}
+			defer client.Close()
+
+			message := &OSCMessage{Address: "/hello", Args: []OSCArg{{Type: "i", Value: int32(6)}}}
+			err = client.WritePacket(message)
+			if err != nil {
+				t.Fatalf("failed to write packet: %s", err.Error())
+			}
+
+			server.SetReadDeadline(time.Now().Add(time.Second))
+			got, from, err := server.ReadPacket()
+			if err != nil {
+				t.Fatalf("failed to read packet: %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, message) {
+				t.Fatalf("failed to receive packet got '%v', expected '%v'", got, message)
+			}
+
+			reply := &OSCMessage{Address: "/reply", Args: []OSCArg{}}
+			err = server.WritePacketTo(reply, from)
+			if err != nil {
+				t.Fatalf("failed to write reply: %s", err.Error())
+			}
+
+			client.SetReadDeadline(time.Now().Add(time.Second))
+			got, _, err = client.ReadPacket()
+			if err != nil {
+				t.Fatalf("failed to read reply: %s", err.Error())
+			}
+			if !reflect.DeepEqual(got, reply) {
+				t.Fatalf("failed to receive reply got '%v', expected '%v'", got, reply)
+			}
+		})
+	}
+}
+
+func TestUDPConnDecodeError(t *testing.T) {
+	server, err := ListenUDP("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer server.Close()
+
+	client, err := net.Dial("udp4", server.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	defer client.Close()
+
+	client.Write([]byte("garbage"))
+	client.Write([]byte{47, 104, 105, 0, 44, 0, 0, 0})
+
+	server.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err = server.ReadPacket()
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("expected a DecodeError but got: %v", err)
+	}
+
+	if string(decodeErr.Bytes) != "garbage" {
+		t.Fatalf("DecodeError got bytes '%v', expected '%v'", decodeErr.Bytes, []byte("garbage"))
+	}
+
+	got, _, err := server.ReadPacket()
+	if err != nil {
+		t.Fatalf("failed to read packet after DecodeError: %s", err.Error())
+	}
+
+	expected := &OSCMessage{Address: "/hi", Args: []OSCArg{}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("failed to receive packet got '%v', expected '%v'", got, expected)
+	}
+}
+
+func TestUDPConnWriteWithoutRemote(t *testing.T) {
+	conn, err := ListenUDP("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer conn.Close()
+
+	err = conn.WritePacket(&OSCMessage{Address: "/hello"})
+	if err == nil {
+		t.Fatalf("WritePacket expected to fail without a remote address")
+	}
+}
+
+func multicastInterface(t *testing.T) *net.Interface {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		t.Skipf("unable to list interfaces: %s", err.Error())
+	}
+	for _, ifi := range interfaces {
+		if ifi.Flags&net.FlagUp != 0 && ifi.Flags&net.FlagMulticast != 0 {
+			addrs, err := ifi.Addrs()
+			if err == nil && len(addrs) > 0 {
+				return &ifi
+			}
+		}
+	}
+	t.Skip("no multicast capable interface available")
+	return nil
+}
+
+func TestUDPMulticastLoopback(t *testing.T) {
+	ifi := multicastInterface(t)
+
+	config := UDPConfig{
+		MulticastInterface: ifi,
+	}
+
+	server, err := config.ListenMulticast("udp4", "239.255.77.77:0")
+	if err != nil {
+		t.Skipf("unable to join multicast group: %s", err.Error())
+	}
+	defer server.Close()
+
+	port := server.LocalAddr().(*net.UDPAddr).Port
+	client, err := config.Dial("udp4", net.JoinHostPort("239.255.77.77", strconv.Itoa(port)))
+	if err != nil {
+		t.Fatalf("failed to dial multicast group: %s", err.Error())
+	}
+	defer client.Close()
+
+	message := &OSCMessage{Address: "/multicast", Args: []OSCArg{{Type: "i", Value: int32(1)}}}
+	err = client.WritePacket(message)
+	if err != nil {
+		t.Fatalf("failed to write packet: %s", err.Error())
+	}
+
+	server.SetReadDeadline(time.Now().Add(time.Second))
+	got, _, err := server.ReadPacket()
+	if err != nil {
+		t.Skipf("multicast packet not looped back: %s", err.Error())
+	}
+
+	if !reflect.DeepEqual(got, message) {
+		t.Fatalf("failed to receive packet got '%v', expected '%v'", got, message)
+	}
+}
+
+func TestUDPBroadcast(t *testing.T) {
+	server, err := ListenUDP("udp4", "0.0.0.0:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer server.Close()
+
+	broadcastAddress := net.JoinHostPort("255.255.255.255", strconv.Itoa(server.LocalAddr().(*net.UDPAddr).Port))
+
+	withoutBroadcast, err := DialUDP("udp4", broadcastAddress)
+	if err != nil {
+		t.Skipf("unable to dial broadcast address: %s", err.Error())
+	}
+	defer withoutBroadcast.Close()
+
+	if withoutBroadcast.WritePacket(&OSCMessage{Address: "/broadcast"}) == nil {
+		t.Skip("platform allows broadcast without SO_BROADCAST")
+	}
+
+	client, err := UDPConfig{Broadcast: true}.Dial("udp4", broadcastAddress)
+	if err != nil {
+		t.Fatalf("failed to dial broadcast address: %s", err.Error())
+	}
+	defer client.Close()
+
+	err = client.WritePacket(&OSCMessage{Address: "/broadcast"})
+	if err != nil {
+		t.Fatalf("failed to write broadcast packet: %s", err.Error())
+	}
+}
+
+func TestUDPConnMaxPacketSize(t *testing.T) {
+	listener, err := ListenUDP("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err.Error())
+	}
+	defer listener.Close()
+
+	client, err := UDPConfig{MaxPacketSize: 48}.Dial("udp4", listener.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	defer client.Close()
+
+	timeTag := ImmediateTimeTag()
+	a := &OSCMessage{Address: "/a", Args: []OSCArg{{Type: "i", Value: int32(1)}}}
+	b := &OSCMessage{Address: "/b", Args: []OSCArg{{Type: "i", Value: int32(2)}}}
+	c := &OSCMessage{Address: "/c", Args: []OSCArg{{Type: "i", Value: int32(3)}}}
+
+	err = client.WritePacket(&OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a, b, c}})
+	if err != nil {
+		t.Fatalf("failed to write bundle: %s", err.Error())
+	}
+
+	expected := []OSCPacket{
+		&OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{a, b}},
+		&OSCBundle{TimeTag: timeTag, Contents: []OSCPacket{c}},
+	}
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	for _, want := range expected {
+		got, _, err := listener.ReadPacket()
+		if err != nil {
+			t.Fatalf("failed to read packet: %s", err.Error())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("failed to split bundle got '%v', expected '%v'", got, want)
+		}
+	}
+
+	big := &OSCMessage{Address: "/big", Args: []OSCArg{{Type: "b", Value: make([]byte, 64)}}}
+	err = client.WritePacket(big)
+	if err == nil {
+		t.Fatalf("expected message over the limit to fail")
+	}
+	if err.Error() != "OSC message /big is 80 bytes which is over the 48 byte limit" {
+		t.Fatalf("failed to reject message got '%s'", err.Error())
+	}
+}
@@ -0,0 +1,51 @@
+package osc
+
+import (
+	"net"
+)
+
+// UnixgramConn sends one OSC packet per datagram over a unix domain socket
+type UnixgramConn struct {
+	*net.UnixConn
+	buffer []byte
+}
+
+// DialUnixgram connects to the socket at address, localAddress can be empty when no replies are expected
+func DialUnixgram(localAddress string, address string) (*UnixgramConn, error) {
+	var localAddr *net.UnixAddr
+	if localAddress != "" {
+		localAddr = &net.UnixAddr{Name: localAddress, Net: "unixgram"}
+	}
+	conn, err := net.DialUnix("unixgram", localAddr, &net.UnixAddr{Name: address, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return newUnixgramConn(conn), nil
+}
+
+func ListenUnixgram(address string) (*UnixgramConn, error) {
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: address, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return newUnixgramConn(conn), nil
+}
+
+func newUnixgramConn(conn *net.UnixConn) *UnixgramConn {
+	return &UnixgramConn{
+		UnixConn: conn,
+		buffer:   make([]byte, maxDatagramSize),
+	}
+}
+
+func (c *UnixgramConn) ReadPacket() (OSCPacket, net.Addr, error) {
+	return readDatagramPacket(c.UnixConn, c.buffer)
+}
+
+func (c *UnixgramConn) WritePacket(packet OSCPacket) error {
+	return writeDatagramPacket(c.UnixConn, packet)
+}
+
+func (c *UnixgramConn) WritePacketTo(packet OSCPacket, addr net.Addr) error {
+	return writeDatagramPacketTo(c.UnixConn, packet, addr)
+}
@@ -0,0 +1,124 @@
+package osc
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+
+	"golang.org/x/net/websocket"
+)
+
+type webSocketFrame struct {
+	data        []byte
+	payloadType byte
+}
+
+var webSocketCodec = websocket.Codec{
+	Marshal: func(v any) ([]byte, byte, error) {
+		frame, ok := v.(webSocketFrame)
+		if !ok {
+			return nil, websocket.UnknownFrame, websocket.ErrNotSupported
+		}
+		return frame.data, frame.payloadType, nil
+	},
+	Unmarshal: func(data []byte, payloadType byte, v any) error {
+		frame, ok := v.(*webSocketFrame)
+		if !ok {
+			return websocket.ErrNotSupported
+		}
+		frame.data = data
+		frame.payloadType = payloadType
+		return nil
+	},
+}
+
+// WebSocketConn carries one OSC packet per WebSocket frame, binary frames hold OSC bytes and text frames hold packet JSON
+type WebSocketConn struct {
+	ws         *websocket.Conn
+	jsonFrames bool
+	mutex      sync.Mutex
+}
+
+// NewWebSocketConn wraps ws, writes use text frames of packet JSON if jsonFrames is set or once the peer has sent one
+func NewWebSocketConn(ws *websocket.Conn, jsonFrames bool) *WebSocketConn {
+	return &WebSocketConn{
+		ws:         ws,
+		jsonFrames: jsonFrames,
+	}
+}
+
+func DialWebSocket(url string, origin string, jsonFrames bool) (*WebSocketConn, error) {
+	ws, err := websocket.Dial(url, "", origin)
+	if err != nil {
+		return nil, err
+	}
+	return NewWebSocketConn(ws, jsonFrames), nil
+}
+
+func (c *WebSocketConn) ReadPacket() (OSCPacket, net.Addr, error) {
+	var frame webSocketFrame
+	if err := webSocketCodec.Receive(c.ws, &frame); err != nil {
+		return nil, c.RemoteAddr(), err
+	}
+
+	var packet OSCPacket
+	var err error
+	if frame.payloadType == websocket.TextFrame {
+		c.mutex.Lock()
+		c.jsonFrames = true
+		c.mutex.Unlock()
+		packet, err = PacketFromJSON(frame.data)
+	} else {
+		packet, _, err = PacketFromBytes(frame.data)
+	}
+	if err != nil {
+		return nil, c.RemoteAddr(), &DecodeError{Bytes: frame.data, Err: err}
+	}
+	return packet, c.RemoteAddr(), nil
+}
+
+func (c *WebSocketConn) WritePacket(packet OSCPacket) error {
+	c.mutex.Lock()
+	jsonFrames := c.jsonFrames
+	c.mutex.Unlock()
+
+	if jsonFrames {
+		data, err := json.Marshal(packet)
+		if err != nil {
+			return err
+		}
+		return webSocketCodec.Send(c.ws, webSocketFrame{data: data, payloadType: websocket.TextFrame})
+	}
+
+	data, err := packet.ToBytes()
+	if err != nil {
+		return err
+	}
+	return webSocketCodec.Send(c.ws, webSocketFrame{data: data, payloadType: websocket.BinaryFrame})
+}
+
+func (c *WebSocketConn) Close() error {
+	return c.ws.Close()
+}
+
+// RemoteAddr is the address of the peer, for server side connections this is the HTTP client address
+func (c *WebSocketConn) RemoteAddr() net.Addr {
+	if request := c.ws.Request(); request != nil {
+		if addr, err := net.ResolveTCPAddr("tcp", request.RemoteAddr); err == nil {
+			return addr
+		}
+	}
+	return c.ws.RemoteAddr()
+}
+
+// WebSocketHandler serves OSC over WebSocket to s.Handler, mount it on any path of an http.Server
+func (s *Server) WebSocketHandler() http.Handler {
+	return websocket.Handler(func(ws *websocket.Conn) {
+		conn := NewWebSocketConn(ws, false)
+		if err := s.Serve(conn); err != nil && !errors.Is(err, net.ErrClosed) {
+			s.reportError(err, conn.RemoteAddr())
+		}
+	})
+}
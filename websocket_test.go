@@ -0,0 +1,87 @@
+package osc
+
+import (
+	"net"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestWebSocketDispatcherEcho(t *testing.T) {
+	dispatcher := NewDispatcher()
+	dispatcher.Handle("/echo", func(w PacketWriter, message *OSCMessage, source net.Addr) {
+		w.WritePacket(message)
+	})
+
+	server := &Server{Handler: dispatcher}
+	httpServer := httptest.NewServer(server.WebSocketHandler())
+	defer httpServer.Close()
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http")
+
+	testCases := []struct {
+		name       string
+		jsonFrames bool
+	}{
+		{name: "binary frames", jsonFrames: false},
+		{name: "json frames", jsonFrames: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			client, err := DialWebSocket(url, httpServer.URL, testCase.jsonFrames)
+			if err != nil {
+				t.Fatalf("failed to dial: %s", err.Error())
+			}
+			defer client.Close()
+
+			message := &OSCMessage{Address: "/echo", Args: []OSCArg{{Type: "s", Value: "hello"}, {Type: "f", Value: float32(0.5)}}}
+			err = client.WritePacket(message)
+			if err != nil {
+				t.Fatalf("failed to write packet: %s", err.Error())
+			}
+
+			got, _, err := client.ReadPacket()
+			if err != nil {
+				t.Fatalf("failed to read echo: %s", err.Error())
+			}
+
+			if !reflect.DeepEqual(got, message) {
+				t.Fatalf("failed to echo packet got '%v', expected '%v'", got, message)
+			}
+		})
+	}
+}
+
+func TestWebSocketJSONReplies(t *testing.T) {
+	server := &Server{Handler: HandlerFunc(func(w PacketWriter, packet OSCPacket, source net.Addr) {
+		w.WritePacket(packet)
+	})}
+	httpServer := httptest.NewServer(server.WebSocketHandler())
+	defer httpServer.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), "", httpServer.URL)
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err.Error())
+	}
+	defer ws.Close()
+
+	err = websocket.Message.Send(ws, `{"address":"/hello","args":[{"type":"i","value":1}]}`)
+	if err != nil {
+		t.Fatalf("failed to send JSON frame: %s", err.Error())
+	}
+
+	var reply string
+	err = websocket.Message.Receive(ws, &reply)
+	if err != nil {
+		t.Fatalf("failed to receive reply: %s", err.Error())
+	}
+
+	expected := `{"address":"/hello","args":[{"value":1,"type":"i"}]}`
+	if reply != expected {
+		t.Fatalf("failed to reply with JSON got '%s', expected '%s'", reply, expected)
+	}
+}